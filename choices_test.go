@@ -0,0 +1,66 @@
+package go_subcommand
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/arran4/go-subcommand/parsers"
+)
+
+const choicesSource = `package main
+
+// Root is a subcommand ` + "`app`" + `
+func Root() {}
+
+// Render is a subcommand ` + "`app render`" + ` -- Render a report
+//
+// Flags:
+//
+//	format: -f --format (choices: json, yaml, table) (default: "table") Output format
+//	kind: @1 (choices: daily, weekly) Report kind
+func Render(format string, kind string) {}
+`
+
+func TestChoices_GeneratedValidation(t *testing.T) {
+	writer := runGenerateInMemory(t, setupProject(t, choicesSource))
+
+	renderGo := string(mustGeneratedFile(t, writer, "cmd/app/render.go"))
+	assertContains(t, renderGo, `case "json", "yaml", "table":`, "flag values should be checked against the choices")
+	assertContains(t, renderGo, `must be one of: json, yaml, table`, "the error should list the valid values")
+	assertContains(t, renderGo, `case "daily", "weekly":`, "positional values should be checked against the choices")
+
+	usage := string(mustGeneratedFile(t, writer, "cmd/app/templates/render_usage.txt"))
+	assertContains(t, usage, `Output format (one of: json, yaml, table)`, "usage should list flag choices")
+	assertContains(t, usage, `Report kind (one of: daily, weekly)`, "usage should list argument choices")
+}
+
+func TestChoices_Completion(t *testing.T) {
+	writer := NewCollectingFileWriter()
+	if err := GenerateWithFS(setupProject(t, choicesSource), writer, ".", "", "completions", "commentv1", nil, false, false, nil, false, false, "", "", ""); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	bash := string(mustGeneratedFile(t, writer, "completions/app.bash"))
+	assertContains(t, bash, `'render:--format'|'render:-f')`, "bash should match the flag before the cursor")
+	assertContains(t, bash, `compgen -W 'json yaml table'`, "bash should offer the choices")
+
+	fish := string(mustGeneratedFile(t, writer, "completions/app.fish"))
+	assertContains(t, fish, `-x -a 'json yaml table'`, "fish should offer the choices")
+
+	ps := string(mustGeneratedFile(t, writer, "completions/app.ps1"))
+	assertContains(t, ps, `'render:--format' = @(`, "powershell should map the flag to its choices")
+}
+
+func TestChoices_InvalidDefault(t *testing.T) {
+	src := strings.Replace(choicesSource, `(default: "table")`, `(default: "csv")`, 1)
+	dataModel, err := parse(".", "commentv1", &parsers.ParseOptions{
+		SearchPaths: []string{"."},
+		Recursive:   true,
+	}, setupProject(t, src))
+	if err == nil {
+		err = dataModel.Validate()
+	}
+	if err == nil || !strings.Contains(err.Error(), `default "csv" of parameter format is not one of its choices`) {
+		t.Fatalf("expected an invalid default error, got %v", err)
+	}
+}
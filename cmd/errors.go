@@ -2,7 +2,10 @@
 
 package cmd
 
-import "errors"
+import (
+	"errors"
+	"reflect"
+)
 
 // ErrPrintHelp when returned by any function anywhere it will switch the command from whatever it is to help.
 var ErrPrintHelp = errors.New("print help")
@@ -10,6 +13,13 @@ var ErrPrintHelp = errors.New("print help")
 // ErrHelp tells the user to use help.
 var ErrHelp = errors.New("help requested")
 
+// ErrUsage matches, with errors.Is, the errors reporting a mistake on the command line:
+// an unknown command or flag, a missing required flag or a value that does not parse.
+var ErrUsage = errors.New("usage error")
+
+// ExitUsage is the exit code for usage errors, EX_USAGE of sysexits.h.
+const ExitUsage = 64
+
 // ErrExitCode Mostly used as a pass through, it's caught, but if the sub error is nil and it's not wrapped in another error, it counts as no error.
 type ErrExitCode struct {
 	Err  error
@@ -26,3 +36,53 @@ func (e *ErrExitCode) Error() string {
 func (e *ErrExitCode) Unwrap() error {
 	return e.Err
 }
+
+type exitCode struct {
+	matches func(err error) bool
+	code    int
+}
+
+var exitCodes []exitCode
+
+// RegisterExitCode makes ExitCode return code for the errors matching target with errors.Is.
+func RegisterExitCode(target error, code int) {
+	exitCodes = append(exitCodes, exitCode{
+		matches: func(err error) bool { return errors.Is(err, target) },
+		code:    code,
+	})
+}
+
+// RegisterExitCodeAs makes ExitCode return code for the errors matching the type target
+// points to with errors.As, e.g. RegisterExitCodeAs(new(*NotFoundError), 3).
+func RegisterExitCodeAs(target any, code int) {
+	t := reflect.TypeOf(target)
+	if t == nil || t.Kind() != reflect.Ptr {
+		panic("cmd: RegisterExitCodeAs target must be a non-nil pointer")
+	}
+	exitCodes = append(exitCodes, exitCode{
+		matches: func(err error) bool { return errors.As(err, reflect.New(t.Elem()).Interface()) },
+		code:    code,
+	})
+}
+
+// ExitCode returns the exit code for err: 0 for nil, the Code of an ErrExitCode, the
+// code of the first registered error err matches, ExitUsage for a usage error and 1
+// for any other error.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var e *ErrExitCode
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	for _, c := range exitCodes {
+		if c.matches(err) {
+			return c.code
+		}
+	}
+	if errors.Is(err, ErrUsage) {
+		return ExitUsage
+	}
+	return 1
+}
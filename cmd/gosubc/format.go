@@ -3,6 +3,7 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
@@ -33,20 +34,31 @@ type UsageDataFormat struct {
 }
 
 func (c *Format) Usage() {
-	err := executeUsage(os.Stderr, "format_usage.txt", UsageDataFormat{c, false})
+	err := executeUsage(c.IO.Stderr(), "format_usage.txt", UsageDataFormat{c, false})
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error generating usage: %s\n", err)
+		fmt.Fprintf(c.IO.Stderr(), "Error generating usage: %s\n", err)
 	}
 }
 
 func (c *Format) UsageRecursive() {
-	err := executeUsage(os.Stderr, "format_usage.txt", UsageDataFormat{c, true})
+	err := executeUsage(c.IO.Stderr(), "format_usage.txt", UsageDataFormat{c, true})
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error generating usage: %s\n", err)
+		fmt.Fprintf(c.IO.Stderr(), "Error generating usage: %s\n", err)
 	}
 }
 
+// subCommands returns the commands below format, for help to look through.
+func (c *Format) subCommands() map[string]func() Cmd {
+	return c.SubCommands
+}
+
+// Execute runs the command with a background context.
 func (c *Format) Execute(args []string) error {
+	return c.ExecuteContext(context.Background(), args)
+}
+
+// ExecuteContext runs the command, passing ctx on to subcommands and to context.Context parameters.
+func (c *Format) ExecuteContext(ctx context.Context, args []string) error {
 	var remainingArgs []string
 	dashDashSeen := false
 	for i := 0; i < len(args); i++ {
@@ -73,52 +85,48 @@ func (c *Format) Execute(args []string) error {
 			_ = value
 			_ = hasValue
 			switch name {
-
 			case "dir":
 				if !hasValue {
 					if i+1 < len(args) {
 						value = args[i+1]
 						i++
 					} else {
-						return fmt.Errorf("flag %s requires a value", name)
+						return usageErrorf("flag %s requires a value", name)
 					}
 				}
 				c.dir = value
-
 			case "inplace":
 				if hasValue {
 					b, err := strconv.ParseBool(value)
 					if err != nil {
-						return fmt.Errorf("invalid boolean value for flag %s: %s", name, value)
+						return usageErrorf("invalid boolean value for flag %s: %s", name, value)
 					}
 					c.inplace = b
 				} else {
 					c.inplace = true
 				}
-
 			case "paths", "path":
 				if !hasValue {
 					if i+1 < len(args) {
 						value = args[i+1]
 						i++
 					} else {
-						return fmt.Errorf("flag %s requires a value", name)
+						return usageErrorf("flag %s requires a value", name)
 					}
 				}
 				c.paths = append(c.paths, value)
-
 			case "recursive":
 				if hasValue {
 					b, err := strconv.ParseBool(value)
 					if err != nil {
-						return fmt.Errorf("invalid boolean value for flag %s: %s", name, value)
+						return usageErrorf("invalid boolean value for flag %s: %s", name, value)
 					}
 					c.recursive = b
 				} else {
 					c.recursive = true
 				}
 			default:
-				return fmt.Errorf("unknown flag: --%s", name)
+				return unknownFlagError("--"+name, []string{"--dir", "--inplace", "--path", "--recursive", "--help"})
 			}
 		} else if strings.HasPrefix(arg, "-") && arg != "-" {
 			// Short flags
@@ -130,9 +138,8 @@ func (c *Format) Execute(args []string) error {
 					return nil
 				}
 				found := false
-
 				if !found {
-					return fmt.Errorf("unknown flag: -%s", char)
+					return usageErrorf("unknown flag: -%s", char)
 				}
 			}
 		} else {
@@ -143,8 +150,9 @@ func (c *Format) Execute(args []string) error {
 
 	if !dashDashSeen && len(remainingArgs) > 0 {
 		if cmd, ok := c.SubCommands[remainingArgs[0]]; ok {
-			return cmd().Execute(remainingArgs[1:])
+			return cmd().ExecuteContext(ctx, remainingArgs[1:])
 		}
+		return unknownCommandError(remainingArgs[0], c.SubCommands)
 	}
 
 	if c.CommandAction != nil {
@@ -184,7 +192,7 @@ func (c *RootCmd) NewFormat() *Format {
 				return nil
 			}
 			if errors.Is(err, cmd.ErrHelp) {
-				fmt.Fprintf(os.Stderr, "Use '%s help' for more information.\n", os.Args[0])
+				fmt.Fprintf(c.IO.Stderr(), "Use '%s help' for more information.\n", os.Args[0])
 				return nil
 			}
 			if e, ok := err.(*cmd.ErrExitCode); ok {
@@ -198,12 +206,7 @@ func (c *RootCmd) NewFormat() *Format {
 	v.SubCommands["help"] = func() Cmd {
 		return &InternalCommand{
 			Exec: func(args []string) error {
-				if slices.Contains(args, "-deep") {
-					v.UsageRecursive()
-					return nil
-				}
-				v.Usage()
-				return nil
+				return showHelp(v, v.SubCommands, args)
 			},
 			UsageFunc: v.Usage,
 		}
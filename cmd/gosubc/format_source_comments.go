@@ -3,6 +3,7 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
@@ -32,20 +33,31 @@ type UsageDataFormatSourceComments struct {
 }
 
 func (c *FormatSourceComments) Usage() {
-	err := executeUsage(os.Stderr, "format-source-comments_usage.txt", UsageDataFormatSourceComments{c, false})
+	err := executeUsage(c.IO.Stderr(), "format-source-comments_usage.txt", UsageDataFormatSourceComments{c, false})
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error generating usage: %s\n", err)
+		fmt.Fprintf(c.IO.Stderr(), "Error generating usage: %s\n", err)
 	}
 }
 
 func (c *FormatSourceComments) UsageRecursive() {
-	err := executeUsage(os.Stderr, "format-source-comments_usage.txt", UsageDataFormatSourceComments{c, true})
+	err := executeUsage(c.IO.Stderr(), "format-source-comments_usage.txt", UsageDataFormatSourceComments{c, true})
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error generating usage: %s\n", err)
+		fmt.Fprintf(c.IO.Stderr(), "Error generating usage: %s\n", err)
 	}
 }
 
+// subCommands returns the commands below format-source-comments, for help to look through.
+func (c *FormatSourceComments) subCommands() map[string]func() Cmd {
+	return c.SubCommands
+}
+
+// Execute runs the command with a background context.
 func (c *FormatSourceComments) Execute(args []string) error {
+	return c.ExecuteContext(context.Background(), args)
+}
+
+// ExecuteContext runs the command, passing ctx on to subcommands and to context.Context parameters.
+func (c *FormatSourceComments) ExecuteContext(ctx context.Context, args []string) error {
 	var remainingArgs []string
 	dashDashSeen := false
 	for i := 0; i < len(args); i++ {
@@ -72,41 +84,38 @@ func (c *FormatSourceComments) Execute(args []string) error {
 			_ = value
 			_ = hasValue
 			switch name {
-
 			case "dir":
 				if !hasValue {
 					if i+1 < len(args) {
 						value = args[i+1]
 						i++
 					} else {
-						return fmt.Errorf("flag %s requires a value", name)
+						return usageErrorf("flag %s requires a value", name)
 					}
 				}
 				c.dir = value
-
 			case "paths", "path":
 				if !hasValue {
 					if i+1 < len(args) {
 						value = args[i+1]
 						i++
 					} else {
-						return fmt.Errorf("flag %s requires a value", name)
+						return usageErrorf("flag %s requires a value", name)
 					}
 				}
 				c.paths = append(c.paths, value)
-
 			case "recursive":
 				if hasValue {
 					b, err := strconv.ParseBool(value)
 					if err != nil {
-						return fmt.Errorf("invalid boolean value for flag %s: %s", name, value)
+						return usageErrorf("invalid boolean value for flag %s: %s", name, value)
 					}
 					c.recursive = b
 				} else {
 					c.recursive = true
 				}
 			default:
-				return fmt.Errorf("unknown flag: --%s", name)
+				return unknownFlagError("--"+name, []string{"--dir", "--path", "--recursive", "--help"})
 			}
 		} else if strings.HasPrefix(arg, "-") && arg != "-" {
 			// Short flags
@@ -118,9 +127,8 @@ func (c *FormatSourceComments) Execute(args []string) error {
 					return nil
 				}
 				found := false
-
 				if !found {
-					return fmt.Errorf("unknown flag: -%s", char)
+					return usageErrorf("unknown flag: -%s", char)
 				}
 			}
 		} else {
@@ -131,8 +139,9 @@ func (c *FormatSourceComments) Execute(args []string) error {
 
 	if !dashDashSeen && len(remainingArgs) > 0 {
 		if cmd, ok := c.SubCommands[remainingArgs[0]]; ok {
-			return cmd().Execute(remainingArgs[1:])
+			return cmd().ExecuteContext(ctx, remainingArgs[1:])
 		}
+		return unknownCommandError(remainingArgs[0], c.SubCommands)
 	}
 
 	if c.CommandAction != nil {
@@ -170,7 +179,7 @@ func (c *RootCmd) NewFormatSourceComments() *FormatSourceComments {
 				return nil
 			}
 			if errors.Is(err, cmd.ErrHelp) {
-				fmt.Fprintf(os.Stderr, "Use '%s help' for more information.\n", os.Args[0])
+				fmt.Fprintf(c.IO.Stderr(), "Use '%s help' for more information.\n", os.Args[0])
 				return nil
 			}
 			if e, ok := err.(*cmd.ErrExitCode); ok {
@@ -184,12 +193,7 @@ func (c *RootCmd) NewFormatSourceComments() *FormatSourceComments {
 	v.SubCommands["help"] = func() Cmd {
 		return &InternalCommand{
 			Exec: func(args []string) error {
-				if slices.Contains(args, "-deep") {
-					v.UsageRecursive()
-					return nil
-				}
-				v.Usage()
-				return nil
+				return showHelp(v, v.SubCommands, args)
 			},
 			UsageFunc: v.Usage,
 		}
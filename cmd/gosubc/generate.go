@@ -3,6 +3,7 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
@@ -21,6 +22,7 @@ type Generate struct {
 	Flags             *flag.FlagSet
 	dir               string
 	manDir            string
+	completionDir     string
 	parserName        string
 	paths             []string
 	recursive         bool
@@ -42,20 +44,31 @@ type UsageDataGenerate struct {
 }
 
 func (c *Generate) Usage() {
-	err := executeUsage(os.Stderr, "generate_usage.txt", UsageDataGenerate{c, false})
+	err := executeUsage(c.IO.Stderr(), "generate_usage.txt", UsageDataGenerate{c, false})
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error generating usage: %s\n", err)
+		fmt.Fprintf(c.IO.Stderr(), "Error generating usage: %s\n", err)
 	}
 }
 
 func (c *Generate) UsageRecursive() {
-	err := executeUsage(os.Stderr, "generate_usage.txt", UsageDataGenerate{c, true})
+	err := executeUsage(c.IO.Stderr(), "generate_usage.txt", UsageDataGenerate{c, true})
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error generating usage: %s\n", err)
+		fmt.Fprintf(c.IO.Stderr(), "Error generating usage: %s\n", err)
 	}
 }
 
+// subCommands returns the commands below generate, for help to look through.
+func (c *Generate) subCommands() map[string]func() Cmd {
+	return c.SubCommands
+}
+
+// Execute runs the command with a background context.
 func (c *Generate) Execute(args []string) error {
+	return c.ExecuteContext(context.Background(), args)
+}
+
+// ExecuteContext runs the command, passing ctx on to subcommands and to context.Context parameters.
+func (c *Generate) ExecuteContext(ctx context.Context, args []string) error {
 	var remainingArgs []string
 	dashDashSeen := false
 	for i := 0; i < len(args); i++ {
@@ -82,151 +95,148 @@ func (c *Generate) Execute(args []string) error {
 			_ = value
 			_ = hasValue
 			switch name {
-
 			case "dir":
 				if !hasValue {
 					if i+1 < len(args) {
 						value = args[i+1]
 						i++
 					} else {
-						return fmt.Errorf("flag %s requires a value", name)
+						return usageErrorf("flag %s requires a value", name)
 					}
 				}
 				c.dir = value
-
 			case "manDir", "man-dir":
 				if !hasValue {
 					if i+1 < len(args) {
 						value = args[i+1]
 						i++
 					} else {
-						return fmt.Errorf("flag %s requires a value", name)
+						return usageErrorf("flag %s requires a value", name)
 					}
 				}
 				c.manDir = value
-
+			case "completionDir", "completion-dir":
+				if !hasValue {
+					if i+1 < len(args) {
+						value = args[i+1]
+						i++
+					} else {
+						return usageErrorf("flag %s requires a value", name)
+					}
+				}
+				c.completionDir = value
 			case "parserName", "parser-name":
 				if !hasValue {
 					if i+1 < len(args) {
 						value = args[i+1]
 						i++
 					} else {
-						return fmt.Errorf("flag %s requires a value", name)
+						return usageErrorf("flag %s requires a value", name)
 					}
 				}
 				c.parserName = value
-
 			case "paths", "path":
 				if !hasValue {
 					if i+1 < len(args) {
 						value = args[i+1]
 						i++
 					} else {
-						return fmt.Errorf("flag %s requires a value", name)
+						return usageErrorf("flag %s requires a value", name)
 					}
 				}
 				c.paths = append(c.paths, value)
-
 			case "recursive":
 				if hasValue {
 					b, err := strconv.ParseBool(value)
 					if err != nil {
-						return fmt.Errorf("invalid boolean value for flag %s: %s", name, value)
+						return usageErrorf("invalid boolean value for flag %s: %s", name, value)
 					}
 					c.recursive = b
 				} else {
 					c.recursive = true
 				}
-
 			case "force":
 				if hasValue {
 					b, err := strconv.ParseBool(value)
 					if err != nil {
-						return fmt.Errorf("invalid boolean value for flag %s: %s", name, value)
+						return usageErrorf("invalid boolean value for flag %s: %s", name, value)
 					}
 					c.force = b
 				} else {
 					c.force = true
 				}
-
 			case "clean":
 				if hasValue {
 					b, err := strconv.ParseBool(value)
 					if err != nil {
-						return fmt.Errorf("invalid boolean value for flag %s: %s", name, value)
+						return usageErrorf("invalid boolean value for flag %s: %s", name, value)
 					}
 					c.clean = b
 				} else {
 					c.clean = true
 				}
-
 			case "replaceTemplates", "replace-template":
 				if !hasValue {
 					if i+1 < len(args) {
 						value = args[i+1]
 						i++
 					} else {
-						return fmt.Errorf("flag %s requires a value", name)
+						return usageErrorf("flag %s requires a value", name)
 					}
 				}
 				c.replaceTemplates = append(c.replaceTemplates, value)
-
 			case "projectProvenance", "project-provenance", "project":
 				if hasValue {
 					b, err := strconv.ParseBool(value)
 					if err != nil {
-						return fmt.Errorf("invalid boolean value for flag %s: %s", name, value)
+						return usageErrorf("invalid boolean value for flag %s: %s", name, value)
 					}
 					c.projectProvenance = b
 				} else {
 					c.projectProvenance = true
 				}
-
 			case "timestamp":
 				if hasValue {
 					b, err := strconv.ParseBool(value)
 					if err != nil {
-						return fmt.Errorf("invalid boolean value for flag %s: %s", name, value)
+						return usageErrorf("invalid boolean value for flag %s: %s", name, value)
 					}
 					c.timestamp = b
 				} else {
 					c.timestamp = true
 				}
-
 			case "provVersion", "prov-version":
 				if !hasValue {
 					if i+1 < len(args) {
 						value = args[i+1]
 						i++
 					} else {
-						return fmt.Errorf("flag %s requires a value", name)
+						return usageErrorf("flag %s requires a value", name)
 					}
 				}
 				c.provVersion = value
-
 			case "provCommit", "prov-commit":
 				if !hasValue {
 					if i+1 < len(args) {
 						value = args[i+1]
 						i++
 					} else {
-						return fmt.Errorf("flag %s requires a value", name)
+						return usageErrorf("flag %s requires a value", name)
 					}
 				}
 				c.provCommit = value
-
 			case "provDate", "prov-date":
 				if !hasValue {
 					if i+1 < len(args) {
 						value = args[i+1]
 						i++
 					} else {
-						return fmt.Errorf("flag %s requires a value", name)
+						return usageErrorf("flag %s requires a value", name)
 					}
 				}
 				c.provDate = value
 			default:
-				return fmt.Errorf("unknown flag: --%s", name)
+				return unknownFlagError("--"+name, []string{"--dir", "--man-dir", "--completion-dir", "--parser-name", "--path", "--recursive", "--force", "--clean", "--replace-template", "--project-provenance", "--project", "--timestamp", "--prov-version", "--prov-commit", "--prov-date", "--help"})
 			}
 		} else if strings.HasPrefix(arg, "-") && arg != "-" {
 			// Short flags
@@ -238,9 +248,8 @@ func (c *Generate) Execute(args []string) error {
 					return nil
 				}
 				found := false
-
 				if !found {
-					return fmt.Errorf("unknown flag: -%s", char)
+					return usageErrorf("unknown flag: -%s", char)
 				}
 			}
 		} else {
@@ -251,8 +260,9 @@ func (c *Generate) Execute(args []string) error {
 
 	if !dashDashSeen && len(remainingArgs) > 0 {
 		if cmd, ok := c.SubCommands[remainingArgs[0]]; ok {
-			return cmd().Execute(remainingArgs[1:])
+			return cmd().ExecuteContext(ctx, remainingArgs[1:])
 		}
+		return unknownCommandError(remainingArgs[0], c.SubCommands)
 	}
 
 	if c.CommandAction != nil {
@@ -278,6 +288,8 @@ func (c *RootCmd) NewGenerate() *Generate {
 
 	set.StringVar(&v.manDir, "man-dir", "", "Directory to generate man pages in optional")
 
+	set.StringVar(&v.completionDir, "completion-dir", "", "Directory to write shell completion scripts to optional")
+
 	set.StringVar(&v.parserName, "parser-name", "commentv1", "Name of the parser to use")
 
 	set.Var((*StringSlice)(&v.paths), "path", "Paths to search for subcommands (relative to dir)")
@@ -304,14 +316,14 @@ func (c *RootCmd) NewGenerate() *Generate {
 
 	v.CommandAction = func(c *Generate) error {
 
-		err := go_subcommand.Generate(c.dir, c.manDir, c.parserName, c.paths, c.recursive, c.force, c.clean, c.replaceTemplates, c.projectProvenance, c.timestamp, c.provVersion, c.provCommit, c.provDate)
+		err := go_subcommand.Generate(c.dir, c.manDir, c.completionDir, c.parserName, c.paths, c.recursive, c.force, c.clean, c.replaceTemplates, c.projectProvenance, c.timestamp, c.provVersion, c.provCommit, c.provDate)
 		if err != nil {
 			if errors.Is(err, cmd.ErrPrintHelp) {
 				c.Usage()
 				return nil
 			}
 			if errors.Is(err, cmd.ErrHelp) {
-				fmt.Fprintf(os.Stderr, "Use '%s help' for more information.\n", os.Args[0])
+				fmt.Fprintf(c.IO.Stderr(), "Use '%s help' for more information.\n", os.Args[0])
 				return nil
 			}
 			if e, ok := err.(*cmd.ErrExitCode); ok {
@@ -325,12 +337,7 @@ func (c *RootCmd) NewGenerate() *Generate {
 	v.SubCommands["help"] = func() Cmd {
 		return &InternalCommand{
 			Exec: func(args []string) error {
-				if slices.Contains(args, "-deep") {
-					v.UsageRecursive()
-					return nil
-				}
-				v.Usage()
-				return nil
+				return showHelp(v, v.SubCommands, args)
 			},
 			UsageFunc: v.Usage,
 		}
@@ -26,6 +26,8 @@ func TestGenerate_Execute(t *testing.T) {
 	args = append(args, "test")
 	args = append(args, "--man-dir")
 	args = append(args, "test")
+	args = append(args, "--completion-dir")
+	args = append(args, "test")
 	args = append(args, "--parser-name")
 	args = append(args, "test")
 	args = append(args, "--path")
@@ -59,6 +61,9 @@ func TestGenerate_Execute(t *testing.T) {
 	if cmd.manDir != "test" {
 		t.Errorf("Expected manDir to be 'test', got '%v'", cmd.manDir)
 	}
+	if cmd.completionDir != "test" {
+		t.Errorf("Expected completionDir to be 'test', got '%v'", cmd.completionDir)
+	}
 	if cmd.parserName != "test" {
 		t.Errorf("Expected parserName to be 'test', got '%v'", cmd.parserName)
 	}
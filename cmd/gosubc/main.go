@@ -5,8 +5,11 @@ package main
 //go:generate sh -c "command -v gosubc >/dev/null 2>&1 && gosubc generate || go run github.com/arran4/go-subcommand/cmd/gosubc generate"
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/arran4/go-subcommand/cmd"
 )
@@ -31,14 +34,13 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err := root.Execute(os.Args[1:]); err != nil {
-		if e, ok := err.(*cmd.ErrExitCode); ok {
-			if e.Err != nil {
-				fmt.Fprintf(os.Stderr, "Error: %v\n", e.Err)
-			}
-			os.Exit(e.Code)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err = root.ExecuteContext(ctx, os.Args[1:])
+	stop()
+	if err != nil {
+		if e, ok := err.(*cmd.ErrExitCode); !ok || e.Err != nil {
+			fmt.Fprintf(root.IO.Stderr(), "Error: %v\n", err)
 		}
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(cmd.ExitCode(err))
 	}
 }
@@ -3,6 +3,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"io"
@@ -11,11 +12,13 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/arran4/go-subcommand/cmd"
 	"github.com/arran4/go-subcommand/cmd/gosubc/templates"
 )
 
 type Cmd interface {
 	Execute(args []string) error
+	ExecuteContext(ctx context.Context, args []string) error
 	Usage()
 }
 
@@ -28,6 +31,10 @@ func (c *InternalCommand) Execute(args []string) error {
 	return c.Exec(args)
 }
 
+func (c *InternalCommand) ExecuteContext(ctx context.Context, args []string) error {
+	return c.Exec(args)
+}
+
 func (c *InternalCommand) Usage() {
 	c.UsageFunc()
 }
@@ -43,9 +50,53 @@ func NewLazyCommand(f func() Cmd) func() Cmd {
 	}
 }
 
+// commandGroup is a command with subcommands of its own, which help looks through to
+// find the command a path names.
+type commandGroup interface {
+	subCommands() map[string]func() Cmd
+}
+
+// showHelp prints the usage of the command that args name among commands, the
+// subcommands of target, or of target itself when they name none. Aliases are followed
+// like any other name, and -deep lists the subcommands recursively.
+func showHelp(target Cmd, commands map[string]func() Cmd, args []string) error {
+	deep := false
+	var path []string
+	for _, arg := range args {
+		if arg == "-deep" {
+			deep = true
+			continue
+		}
+		path = append(path, arg)
+	}
+	for i, name := range path {
+		f := commands[name]
+		if f == nil {
+			err := unknownCommandError(name, commands)
+			if i > 0 {
+				return fmt.Errorf("%s: %w", strings.Join(path[:i], " "), err)
+			}
+			return err
+		}
+		target = f()
+		commands = nil
+		if g, ok := target.(commandGroup); ok {
+			commands = g.subCommands()
+		}
+	}
+	if r, ok := target.(interface{ UsageRecursive() }); ok && deep {
+		r.UsageRecursive()
+		return nil
+	}
+	target.Usage()
+	return nil
+}
+
 type UserError struct {
 	Err error
 	Msg string
+	// usage is set for the mistakes on the command line the generated parser reports.
+	usage bool
 }
 
 func (e *UserError) Error() string {
@@ -59,12 +110,63 @@ func NewUserError(err error, msg string) *UserError {
 	return &UserError{Err: err, Msg: msg}
 }
 
+// Unwrap returns the error the UserError reports, if any.
+func (e *UserError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports the UserErrors made by usageErrorf as a cmd.ErrUsage, which main exits with
+// cmd.ExitUsage for.
+func (e *UserError) Is(target error) bool {
+	return e.usage && target == cmd.ErrUsage
+}
+
+// usageErrorf reports a mistake on the command line, such as an unknown flag or a value
+// that does not parse, as a UserError matching cmd.ErrUsage.
+func usageErrorf(format string, args ...any) error {
+	return &UserError{Msg: fmt.Sprintf(format, args...), usage: true}
+}
+
 func executeUsage(out io.Writer, templateName string, data any) error {
 	return templates.GetTemplates().ExecuteTemplate(out, templateName, data)
 }
 
+// IO holds the streams commands read from and write to. NewRoot connects them to the
+// process's standard streams; tests can swap them before calling Execute. A nil
+// stream falls back to its os counterpart.
+type IO struct {
+	In  io.Reader
+	Out io.Writer
+	Err io.Writer
+}
+
+// Stdin returns the input stream.
+func (s IO) Stdin() io.Reader {
+	if s.In == nil {
+		return os.Stdin
+	}
+	return s.In
+}
+
+// Stdout returns the output stream.
+func (s IO) Stdout() io.Writer {
+	if s.Out == nil {
+		return os.Stdout
+	}
+	return s.Out
+}
+
+// Stderr returns the stream for usage text and diagnostics.
+func (s IO) Stderr() io.Writer {
+	if s.Err == nil {
+		return os.Stderr
+	}
+	return s.Err
+}
+
 type RootCmd struct {
 	*flag.FlagSet
+	IO            IO
 	Commands      map[string]func() Cmd
 	Version       string
 	Commit        string
@@ -73,16 +175,16 @@ type RootCmd struct {
 }
 
 func (c *RootCmd) Usage() {
-	err := executeUsage(os.Stderr, "gosubc_usage.txt", UsageDataRootCmd{c, false})
+	err := executeUsage(c.IO.Stderr(), "gosubc_usage.txt", UsageDataRootCmd{c, false})
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error generating usage: %s\n", err)
+		fmt.Fprintf(c.IO.Stderr(), "Error generating usage: %s\n", err)
 	}
 }
 
 func (c *RootCmd) UsageRecursive() {
-	err := executeUsage(os.Stderr, "gosubc_usage.txt", UsageDataRootCmd{c, true})
+	err := executeUsage(c.IO.Stderr(), "gosubc_usage.txt", UsageDataRootCmd{c, true})
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error generating usage: %s\n", err)
+		fmt.Fprintf(c.IO.Stderr(), "Error generating usage: %s\n", err)
 	}
 }
 
@@ -94,6 +196,7 @@ type UsageDataRootCmd struct {
 func NewRoot(name, version, commit, date string) (*RootCmd, error) {
 	c := &RootCmd{
 		FlagSet:  flag.NewFlagSet(name, flag.ExitOnError),
+		IO:       IO{In: os.Stdin, Out: os.Stdout, Err: os.Stderr},
 		Commands: make(map[string]func() Cmd),
 		Version:  version,
 		Commit:   commit,
@@ -165,12 +268,7 @@ func NewRoot(name, version, commit, date string) (*RootCmd, error) {
 	c.Commands["help"] = func() Cmd {
 		return &InternalCommand{
 			Exec: func(args []string) error {
-				if slices.Contains(args, "-deep") {
-					c.UsageRecursive()
-					return nil
-				}
-				c.Usage()
-				return nil
+				return showHelp(c, c.Commands, args)
 			},
 			UsageFunc: c.Usage,
 		}
@@ -191,18 +289,42 @@ func NewRoot(name, version, commit, date string) (*RootCmd, error) {
 	c.Commands["version"] = func() Cmd {
 		return &InternalCommand{
 			Exec: func(args []string) error {
-				fmt.Printf("Version: %s\nCommit: %s\nDate: %s\n", c.Version, c.Commit, c.Date)
+				fmt.Fprintf(c.IO.Stdout(), "Version: %s\nCommit: %s\nDate: %s\n", c.Version, c.Commit, c.Date)
 				return nil
 			},
 			UsageFunc: func() {
-				fmt.Fprintf(os.Stderr, "Usage: %s version\n", os.Args[0])
+				fmt.Fprintf(c.IO.Stderr(), "Usage: %s version\n", os.Args[0])
+			},
+		}
+	}
+	c.Commands["completion"] = func() Cmd {
+		return &InternalCommand{
+			Exec: func(args []string) error {
+				if len(args) != 1 {
+					return usageErrorf("expected exactly one shell, one of: %s", strings.Join(completionShells, ", "))
+				}
+				script, err := completionScript(args[0])
+				if err != nil {
+					return NewUserError(err, "completion")
+				}
+				fmt.Fprint(c.IO.Stdout(), script)
+				return nil
+			},
+			UsageFunc: func() {
+				fmt.Fprintf(c.IO.Stderr(), "Usage: %s completion <%s>\n", os.Args[0], strings.Join(completionShells, "|"))
 			},
 		}
 	}
 	return c, nil
 }
 
+// Execute runs the command with a background context.
 func (c *RootCmd) Execute(args []string) error {
+	return c.ExecuteContext(context.Background(), args)
+}
+
+// ExecuteContext runs the command, passing ctx on to subcommands and to context.Context parameters.
+func (c *RootCmd) ExecuteContext(ctx context.Context, args []string) error {
 	var remainingArgs []string
 	dashDashSeen := false
 	for i := 0; i < len(args); i++ {
@@ -230,7 +352,7 @@ func (c *RootCmd) Execute(args []string) error {
 			_ = hasValue
 			switch name {
 			default:
-				return fmt.Errorf("unknown flag: --%s", name)
+				return unknownFlagError("--"+name, []string{"--help"})
 			}
 		} else if strings.HasPrefix(arg, "-") && arg != "-" {
 			// Short flags
@@ -243,7 +365,7 @@ func (c *RootCmd) Execute(args []string) error {
 				}
 				found := false
 				if !found {
-					return fmt.Errorf("unknown flag: -%s", char)
+					return usageErrorf("unknown flag: -%s", char)
 				}
 			}
 		} else {
@@ -254,12 +376,12 @@ func (c *RootCmd) Execute(args []string) error {
 
 	if !dashDashSeen && len(remainingArgs) > 0 {
 		if cmd, ok := c.Commands[remainingArgs[0]]; ok {
-			return cmd().Execute(remainingArgs[1:])
+			return cmd().ExecuteContext(ctx, remainingArgs[1:])
 		}
 	}
 	c.Usage()
 	if len(remainingArgs) > 0 {
-		return fmt.Errorf("unknown command: %s", remainingArgs[0])
+		return unknownCommandError(remainingArgs[0], c.Commands)
 	}
 	return nil
 }
@@ -3,9 +3,9 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
-	"os"
 	"slices"
 	"strings"
 )
@@ -25,20 +25,31 @@ type UsageDataSkill struct {
 }
 
 func (c *Skill) Usage() {
-	err := executeUsage(os.Stderr, "skill_usage.txt", UsageDataSkill{c, false})
+	err := executeUsage(c.IO.Stderr(), "skill_usage.txt", UsageDataSkill{c, false})
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error generating usage: %s\n", err)
+		fmt.Fprintf(c.IO.Stderr(), "Error generating usage: %s\n", err)
 	}
 }
 
 func (c *Skill) UsageRecursive() {
-	err := executeUsage(os.Stderr, "skill_usage.txt", UsageDataSkill{c, true})
+	err := executeUsage(c.IO.Stderr(), "skill_usage.txt", UsageDataSkill{c, true})
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error generating usage: %s\n", err)
+		fmt.Fprintf(c.IO.Stderr(), "Error generating usage: %s\n", err)
 	}
 }
 
+// subCommands returns the commands below skill, for help to look through.
+func (c *Skill) subCommands() map[string]func() Cmd {
+	return c.SubCommands
+}
+
+// Execute runs the command with a background context.
 func (c *Skill) Execute(args []string) error {
+	return c.ExecuteContext(context.Background(), args)
+}
+
+// ExecuteContext runs the command, passing ctx on to subcommands and to context.Context parameters.
+func (c *Skill) ExecuteContext(ctx context.Context, args []string) error {
 	var remainingArgs []string
 	dashDashSeen := false
 	for i := 0; i < len(args); i++ {
@@ -66,7 +77,7 @@ func (c *Skill) Execute(args []string) error {
 			_ = hasValue
 			switch name {
 			default:
-				return fmt.Errorf("unknown flag: --%s", name)
+				return unknownFlagError("--"+name, []string{"--help"})
 			}
 		} else if strings.HasPrefix(arg, "-") && arg != "-" {
 			// Short flags
@@ -79,7 +90,7 @@ func (c *Skill) Execute(args []string) error {
 				}
 				found := false
 				if !found {
-					return fmt.Errorf("unknown flag: -%s", char)
+					return usageErrorf("unknown flag: -%s", char)
 				}
 			}
 		} else {
@@ -90,8 +101,9 @@ func (c *Skill) Execute(args []string) error {
 
 	if !dashDashSeen && len(remainingArgs) > 0 {
 		if cmd, ok := c.SubCommands[remainingArgs[0]]; ok {
-			return cmd().Execute(remainingArgs[1:])
+			return cmd().ExecuteContext(ctx, remainingArgs[1:])
 		}
+		return unknownCommandError(remainingArgs[0], c.SubCommands)
 	}
 
 	c.Usage()
@@ -141,12 +153,7 @@ func (c *RootCmd) NewSkill() *Skill {
 	v.SubCommands["help"] = func() Cmd {
 		return &InternalCommand{
 			Exec: func(args []string) error {
-				if slices.Contains(args, "-deep") {
-					v.UsageRecursive()
-					return nil
-				}
-				v.Usage()
-				return nil
+				return showHelp(v, v.SubCommands, args)
 			},
 			UsageFunc: v.Usage,
 		}
@@ -3,6 +3,7 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
@@ -31,20 +32,31 @@ type UsageDataTemplateExport struct {
 }
 
 func (c *TemplateExport) Usage() {
-	err := executeUsage(os.Stderr, "export_usage.txt", UsageDataTemplateExport{c, false})
+	err := executeUsage(c.IO.Stderr(), "export_usage.txt", UsageDataTemplateExport{c, false})
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error generating usage: %s\n", err)
+		fmt.Fprintf(c.IO.Stderr(), "Error generating usage: %s\n", err)
 	}
 }
 
 func (c *TemplateExport) UsageRecursive() {
-	err := executeUsage(os.Stderr, "export_usage.txt", UsageDataTemplateExport{c, true})
+	err := executeUsage(c.IO.Stderr(), "export_usage.txt", UsageDataTemplateExport{c, true})
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error generating usage: %s\n", err)
+		fmt.Fprintf(c.IO.Stderr(), "Error generating usage: %s\n", err)
 	}
 }
 
+// subCommands returns the commands below export, for help to look through.
+func (c *TemplateExport) subCommands() map[string]func() Cmd {
+	return c.SubCommands
+}
+
+// Execute runs the command with a background context.
 func (c *TemplateExport) Execute(args []string) error {
+	return c.ExecuteContext(context.Background(), args)
+}
+
+// ExecuteContext runs the command, passing ctx on to subcommands and to context.Context parameters.
+func (c *TemplateExport) ExecuteContext(ctx context.Context, args []string) error {
 	var remainingArgs []string
 	dashDashSeen := false
 	for i := 0; i < len(args); i++ {
@@ -71,30 +83,28 @@ func (c *TemplateExport) Execute(args []string) error {
 			_ = value
 			_ = hasValue
 			switch name {
-
 			case "output":
 				if !hasValue {
 					if i+1 < len(args) {
 						value = args[i+1]
 						i++
 					} else {
-						return fmt.Errorf("flag %s requires a value", name)
+						return usageErrorf("flag %s requires a value", name)
 					}
 				}
 				c.output = value
-
 			case "asTxtar", "as-txtar":
 				if hasValue {
 					b, err := strconv.ParseBool(value)
 					if err != nil {
-						return fmt.Errorf("invalid boolean value for flag %s: %s", name, value)
+						return usageErrorf("invalid boolean value for flag %s: %s", name, value)
 					}
 					c.asTxtar = b
 				} else {
 					c.asTxtar = true
 				}
 			default:
-				return fmt.Errorf("unknown flag: --%s", name)
+				return unknownFlagError("--"+name, []string{"--output", "--as-txtar", "--help"})
 			}
 		} else if strings.HasPrefix(arg, "-") && arg != "-" {
 			// Short flags
@@ -106,7 +116,6 @@ func (c *TemplateExport) Execute(args []string) error {
 					return nil
 				}
 				found := false
-
 				if char == "o" {
 					found = true
 					// Value flag
@@ -124,14 +133,13 @@ func (c *TemplateExport) Execute(args []string) error {
 							value = args[i+1]
 							i++
 						} else {
-							return fmt.Errorf("flag -%s requires a value", char)
+							return usageErrorf("flag -%s requires a value", char)
 						}
 					}
 					c.output = value
 				}
-
 				if !found {
-					return fmt.Errorf("unknown flag: -%s", char)
+					return usageErrorf("unknown flag: -%s", char)
 				}
 			}
 		} else {
@@ -142,8 +150,9 @@ func (c *TemplateExport) Execute(args []string) error {
 
 	if !dashDashSeen && len(remainingArgs) > 0 {
 		if cmd, ok := c.SubCommands[remainingArgs[0]]; ok {
-			return cmd().Execute(remainingArgs[1:])
+			return cmd().ExecuteContext(ctx, remainingArgs[1:])
 		}
+		return unknownCommandError(remainingArgs[0], c.SubCommands)
 	}
 
 	if c.CommandAction != nil {
@@ -180,7 +189,7 @@ func (c *Template) NewTemplateExport() *TemplateExport {
 				return nil
 			}
 			if errors.Is(err, cmd.ErrHelp) {
-				fmt.Fprintf(os.Stderr, "Use '%s help' for more information.\n", os.Args[0])
+				fmt.Fprintf(c.IO.Stderr(), "Use '%s help' for more information.\n", os.Args[0])
 				return nil
 			}
 			if e, ok := err.(*cmd.ErrExitCode); ok {
@@ -194,12 +203,7 @@ func (c *Template) NewTemplateExport() *TemplateExport {
 	v.SubCommands["help"] = func() Cmd {
 		return &InternalCommand{
 			Exec: func(args []string) error {
-				if slices.Contains(args, "-deep") {
-					v.UsageRecursive()
-					return nil
-				}
-				v.Usage()
-				return nil
+				return showHelp(v, v.SubCommands, args)
 			},
 			UsageFunc: v.Usage,
 		}
@@ -3,10 +3,10 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	go_subcommand "github.com/arran4/go-subcommand"
-	"os"
 	"slices"
 	"strings"
 )
@@ -26,20 +26,31 @@ type UsageDataTemplateLayout struct {
 }
 
 func (c *TemplateLayout) Usage() {
-	err := executeUsage(os.Stderr, "layout_usage.txt", UsageDataTemplateLayout{c, false})
+	err := executeUsage(c.IO.Stderr(), "layout_usage.txt", UsageDataTemplateLayout{c, false})
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error generating usage: %s\n", err)
+		fmt.Fprintf(c.IO.Stderr(), "Error generating usage: %s\n", err)
 	}
 }
 
 func (c *TemplateLayout) UsageRecursive() {
-	err := executeUsage(os.Stderr, "layout_usage.txt", UsageDataTemplateLayout{c, true})
+	err := executeUsage(c.IO.Stderr(), "layout_usage.txt", UsageDataTemplateLayout{c, true})
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error generating usage: %s\n", err)
+		fmt.Fprintf(c.IO.Stderr(), "Error generating usage: %s\n", err)
 	}
 }
 
+// subCommands returns the commands below layout, for help to look through.
+func (c *TemplateLayout) subCommands() map[string]func() Cmd {
+	return c.SubCommands
+}
+
+// Execute runs the command with a background context.
 func (c *TemplateLayout) Execute(args []string) error {
+	return c.ExecuteContext(context.Background(), args)
+}
+
+// ExecuteContext runs the command, passing ctx on to subcommands and to context.Context parameters.
+func (c *TemplateLayout) ExecuteContext(ctx context.Context, args []string) error {
 	var remainingArgs []string
 	dashDashSeen := false
 	for i := 0; i < len(args); i++ {
@@ -67,7 +78,7 @@ func (c *TemplateLayout) Execute(args []string) error {
 			_ = hasValue
 			switch name {
 			default:
-				return fmt.Errorf("unknown flag: --%s", name)
+				return unknownFlagError("--"+name, []string{"--help"})
 			}
 		} else if strings.HasPrefix(arg, "-") && arg != "-" {
 			// Short flags
@@ -80,7 +91,7 @@ func (c *TemplateLayout) Execute(args []string) error {
 				}
 				found := false
 				if !found {
-					return fmt.Errorf("unknown flag: -%s", char)
+					return usageErrorf("unknown flag: -%s", char)
 				}
 			}
 		} else {
@@ -91,8 +102,9 @@ func (c *TemplateLayout) Execute(args []string) error {
 
 	if !dashDashSeen && len(remainingArgs) > 0 {
 		if cmd, ok := c.SubCommands[remainingArgs[0]]; ok {
-			return cmd().Execute(remainingArgs[1:])
+			return cmd().ExecuteContext(ctx, remainingArgs[1:])
 		}
+		return unknownCommandError(remainingArgs[0], c.SubCommands)
 	}
 
 	if c.CommandAction != nil {
@@ -124,12 +136,7 @@ func (c *Template) NewTemplateLayout() *TemplateLayout {
 	v.SubCommands["help"] = func() Cmd {
 		return &InternalCommand{
 			Exec: func(args []string) error {
-				if slices.Contains(args, "-deep") {
-					v.UsageRecursive()
-					return nil
-				}
-				v.Usage()
-				return nil
+				return showHelp(v, v.SubCommands, args)
 			},
 			UsageFunc: v.Usage,
 		}
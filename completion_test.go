@@ -0,0 +1,110 @@
+package go_subcommand
+
+import (
+	"strings"
+	"testing"
+)
+
+const completionSource = `package main
+
+// Root is a subcommand ` + "`app`" + `
+func Root() {}
+
+// Users is a subcommand ` + "`app users`" + ` -- Manage users
+// Aliases: u
+func Users() {}
+
+// UsersCreate is a subcommand ` + "`app users create`" + ` -- Create a user
+//
+// Flags:
+//
+//	name: -n --name Name of the user
+//	admin: --admin Grant admin rights
+func UsersCreate(name string, admin bool) {}
+`
+
+func TestCompletion_CommandGenerated(t *testing.T) {
+	writer := runGenerateInMemory(t, setupProject(t, completionSource))
+
+	rootGo := string(mustGeneratedFile(t, writer, "cmd/app/root.go"))
+	assertContains(t, rootGo, `c.Commands["completion"] = func() Cmd {`, "root should register the completion command")
+	assertContains(t, rootGo, `completionScript(args[0])`, "completion command should look up the requested shell")
+
+	completionGo := string(mustGeneratedFile(t, writer, "cmd/app/gosubc_completion.go"))
+	assertContains(t, completionGo, `var completionShells = []string{"bash", "zsh", "fish", "powershell"}`, "supported shells should be listed")
+	assertContains(t, completionGo, `func completionScript(shell string) (string, error)`, "completion script lookup should be generated")
+	assertContains(t, completionGo, `complete -F _app app`, "bash script should be embedded")
+	assertContains(t, completionGo, `#compdef app`, "zsh script should be embedded")
+	assertContains(t, completionGo, `Register-ArgumentCompleter -Native -CommandName 'app'`, "powershell script should be embedded")
+
+	if _, ok := writer.Files["app.bash"]; ok {
+		t.Errorf("completion scripts should not be written without a completion dir")
+	}
+}
+
+func TestCompletion_CompletionDir(t *testing.T) {
+	writer := NewCollectingFileWriter()
+	if err := GenerateWithFS(setupProject(t, completionSource), writer, ".", "", "completions", "commentv1", nil, false, false, nil, false, false, "", "", ""); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	bash := string(mustGeneratedFile(t, writer, "completions/app.bash"))
+	assertContains(t, bash, `':users'|':u') cmdpath='users' ;;`, "bash should descend into subcommands by name and alias")
+	assertContains(t, bash, `'users:create') cmdpath='users create' ;;`, "bash should descend into nested subcommands")
+	assertContains(t, bash, `words='--name -n --admin --help -h'`, "bash should offer long and short flag names")
+	assertContains(t, bash, `words='users u help usage version completion'`, "bash should offer root commands and built-ins")
+
+	zsh := string(mustGeneratedFile(t, writer, "completions/_app"))
+	assertContains(t, zsh, `'create:Create a user'`, "zsh should describe subcommands")
+
+	fish := string(mustGeneratedFile(t, writer, "completions/app.fish"))
+	assertContains(t, fish, `-l 'name' -s 'n' -r`, "fish should mark value flags as requiring an argument")
+	assertNotContains(t, fish, `-l 'admin' -r`, "fish should not require a value for boolean flags")
+
+	ps := string(mustGeneratedFile(t, writer, "completions/app.ps1"))
+	assertContains(t, ps, `':u' = 'users'`, "powershell should map alias transitions")
+
+	for _, f := range []string{"completions/app.bash", "completions/_app", "completions/app.fish", "completions/app.ps1"} {
+		if !isGenerated(writer.Files[f]) {
+			t.Errorf("%s should carry the generated marker so --clean can remove it", f)
+		}
+	}
+}
+
+func TestCompletion_InheritedFlags(t *testing.T) {
+	writer := NewCollectingFileWriter()
+	source := `package main
+
+// Root is a subcommand ` + "`app`" + `
+//
+// ConfigFile: json
+//
+// Flags:
+//
+//	verbose: -v --verbose (persistent) Print more
+func Root(verbose bool) {}
+
+// Users is a subcommand ` + "`app users`" + ` -- Manage users
+//
+// Flags:
+//
+//	verbose: (from parent)
+func Users(verbose bool) {}
+
+// UsersList is a subcommand ` + "`app users list`" + ` -- List users
+func UsersList() ([]string, error) { return nil, nil }
+`
+	if err := GenerateWithFS(setupProject(t, source), writer, ".", "", "completions", "commentv1", nil, false, false, nil, false, false, "", "", ""); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	bash := string(mustGeneratedFile(t, writer, "completions/app.bash"))
+	assertContains(t, bash, `words='--verbose -v --config --output --help -h'`, "the root should offer --config and --output")
+	assertContains(t, bash, `words='--verbose -v --output --help -h'`, "subcommands should offer the persistent flags of their ancestors once")
+	if n := strings.Count(bash, `--config`); n != 1 {
+		t.Errorf("--config is only parsed by the root command, but is offered %d times", n)
+	}
+
+	fish := string(mustGeneratedFile(t, writer, "completions/app.fish"))
+	assertContains(t, fish, `-n '___app_using_path \'users list\'' -l 'verbose' -s 'v' -d 'Print more'`, "fish should offer an inherited flag on the nested subcommand")
+}
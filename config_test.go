@@ -0,0 +1,64 @@
+package go_subcommand
+
+import (
+	"strings"
+	"testing"
+)
+
+const configSource = `package main
+
+// Root is a subcommand ` + "`app`" + `
+//
+// EnvPrefix: APP
+// ConfigFile: json, ini
+//
+// Flags:
+//
+//	port: --port (default: 8080) Port to listen on
+func Root(port int) {}
+
+// UsersCreate is a subcommand ` + "`app users create`" + ` -- Create a user
+//
+// Flags:
+//
+//	name: --name Name of the user
+func UsersCreate(name string) {}
+`
+
+func TestConfig_Generated(t *testing.T) {
+	writer := runGenerateInMemory(t, setupProject(t, configSource))
+
+	rootGo := string(mustGeneratedFile(t, writer, "cmd/app/root.go"))
+	assertContains(t, rootGo, `case "config":`, "root should accept --config")
+	assertContains(t, rootGo, `c.ConfigFile = os.Getenv("APP_CONFIG")`, "the config path should follow the env prefix")
+	assertContains(t, rootGo, `c.Config["port"]`, "root flags should use the bare flag name as key")
+
+	createGo := string(mustGeneratedFile(t, writer, "cmd/app/users_create.go"))
+	assertContains(t, createGo, `c.RootCmd.Config["users.create.name"]`, "subcommand flags should be keyed by command path")
+
+	configGo := string(mustGeneratedFile(t, writer, "cmd/app/gosubc_config.go"))
+	assertContains(t, configGo, `func parseJSONConfig(`, "json loader should be generated")
+	assertContains(t, configGo, `func parseINIConfig(`, "ini loader should be generated when requested")
+
+	usage := string(mustGeneratedFile(t, writer, "cmd/app/templates/app_usage.txt"))
+	assertContains(t, usage, `--config string`, "usage should list the config flag")
+	assertContains(t, usage, `Load flag values from a JSON or INI file (env: APP_CONFIG)`, "usage should describe the config flag")
+}
+
+func TestConfig_NotGeneratedByDefault(t *testing.T) {
+	writer := runGenerateInMemory(t, setupProject(t, envSource))
+
+	if _, ok := writer.Files["cmd/app/gosubc_config.go"]; ok {
+		t.Error("gosubc_config.go should only be generated with a ConfigFile directive")
+	}
+	assertNotContains(t, string(mustGeneratedFile(t, writer, "cmd/app/root.go")), `case "config":`, "--config should be opt-in")
+}
+
+func TestConfig_ReservedFlag(t *testing.T) {
+	src := strings.Replace(configSource, "port: --port", "port: --config", 1)
+	writer := NewCollectingFileWriter()
+	err := GenerateWithFS(setupProject(t, src), writer, ".", "", "", "commentv1", nil, false, false, nil, false, false, "", "", "")
+	if err == nil || !strings.Contains(err.Error(), "reserved for the config file") {
+		t.Fatalf("err = %v, want reserved flag error", err)
+	}
+}
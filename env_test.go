@@ -0,0 +1,51 @@
+package go_subcommand
+
+import (
+	"testing"
+)
+
+const envSource = `package main
+
+// Root is a subcommand ` + "`app`" + `
+//
+// EnvPrefix: APP
+//
+// Flags:
+//
+//	port: --port (default: 8080) Port to listen on
+func Root(port int) {}
+
+// Serve is a subcommand ` + "`app serve`" + ` -- Serve requests
+//
+// Flags:
+//
+//	host: --host (env: SERVE_HOST) Host to bind
+func Serve(host string) {}
+`
+
+func TestEnv_GeneratedLookup(t *testing.T) {
+	writer := runGenerateInMemory(t, setupProject(t, envSource))
+
+	rootGo := string(mustGeneratedFile(t, writer, "cmd/app/root.go"))
+	assertContains(t, rootGo, `os.LookupEnv("APP_PORT")`, "root flag should fall back to its prefixed variable")
+	assertContains(t, rootGo, `invalid integer value for environment variable APP_PORT`, "env parse errors should name the variable")
+
+	serveGo := string(mustGeneratedFile(t, writer, "cmd/app/serve.go"))
+	assertContains(t, serveGo, `os.LookupEnv("SERVE_HOST")`, "explicit env names should win over the prefix")
+	assertNotContains(t, serveGo, `APP_HOST`, "explicit env names should not be derived from the prefix")
+}
+
+func TestEnv_UsageAndManPage(t *testing.T) {
+	writer := NewCollectingFileWriter()
+	if err := GenerateWithFS(setupProject(t, envSource), writer, ".", "man", "", "commentv1", nil, false, false, nil, false, false, "", "", ""); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	usage := string(mustGeneratedFile(t, writer, "cmd/app/templates/serve_usage.txt"))
+	assertContains(t, usage, `Host to bind (env: SERVE_HOST)`, "usage should list the variable next to the flag")
+
+	man := string(mustGeneratedFile(t, writer, "man/app-serve.1"))
+	assertContains(t, man, ".SH ENVIRONMENT", "man page should have an environment section")
+	assertContains(t, man, ".B SERVE_HOST", "man page should list the variable")
+	assertContains(t, man, ".B APP_PORT", "man page should list inherited variables")
+}
@@ -0,0 +1,35 @@
+package go_subcommand
+
+import (
+	"testing"
+)
+
+const examplesSource = `package main
+
+// Root is a subcommand ` + "`app`" + `
+func Root() {}
+
+// Greet is a subcommand ` + "`app greet`" + ` -- Greet someone
+//
+// Examples:
+//
+//	app greet --name Bob  # Greet Bob
+//	app greet
+func Greet(name string) {}
+`
+
+func TestExamples_ManPageAndAgents(t *testing.T) {
+	writer := NewCollectingFileWriter()
+	if err := GenerateWithFS(setupProject(t, examplesSource), writer, ".", "man", "", "commentv1", nil, false, false, nil, false, false, "", "", ""); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	man := string(mustGeneratedFile(t, writer, "man/app-greet.1"))
+	assertContains(t, man, ".SH EXAMPLES\n\n.TP\n.B app greet \\-\\-name Bob\nGreet Bob\n", "man page should list the examples")
+
+	agents := string(mustGeneratedFile(t, writer, "cmd/agents.md"))
+	assertContains(t, agents, "### app greet\n\n```bash\n# Greet Bob\napp greet --name Bob\napp greet\n```", "agents.md should list the examples")
+
+	test := string(mustGeneratedFile(t, writer, "cmd/app/greet_test.go"))
+	assertContains(t, test, `{"app greet --name Bob", []string{"--name", "Bob"}}`, "each example should become a test case")
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"go/token"
 	"os"
+	"slices"
 	"sort"
 	"strings"
 
@@ -36,7 +37,7 @@ func Format(dir string, inplace bool, paths []string, recursive bool) error {
 	for _, cmd := range dataModel.Commands {
 		// Root command
 		if cmd.DefinitionFile != "" {
-			newDoc := generateDocComment(cmd.FunctionName, cmd.MainCmdName, cmd.Description, cmd.ExtendedHelp, cmd.Parameters)
+			newDoc := generateDocComment(cmd.FunctionName, cmd.Receiver, cmd.MainCmdName, cmd.Description, cmd.ExtendedHelp, cmd.Parameters)
 			editsByFile[cmd.DefinitionFile] = append(editsByFile[cmd.DefinitionFile], fileEdit{
 				start: cmd.DocStart,
 				end:   cmd.DocEnd,
@@ -102,7 +103,7 @@ func collectSubCommandEdits(subCmds []*model.SubCommand, editsByFile map[string]
 		if sc.DefinitionFile != "" {
 			fullSeq := sc.MainCmdName + " " + sc.SubCommandSequence()
 
-			newDoc := generateDocComment(sc.SubCommandFunctionName, fullSeq, sc.SubCommandDescription, sc.SubCommandExtendedHelp, sc.Parameters)
+			newDoc := generateDocComment(sc.SubCommandFunctionName, sc.Receiver, fullSeq, sc.SubCommandDescription, sc.SubCommandExtendedHelp, sc.Parameters)
 			editsByFile[sc.DefinitionFile] = append(editsByFile[sc.DefinitionFile], fileEdit{
 				start: sc.DocStart,
 				end:   sc.DocEnd,
@@ -113,11 +114,19 @@ func collectSubCommandEdits(subCmds []*model.SubCommand, editsByFile map[string]
 	}
 }
 
-func generateDocComment(funcName, commandSeq, description, extendedHelp string, params []*model.FunctionParameter) string {
+func generateDocComment(funcName string, receiver *model.Receiver, commandSeq, description, extendedHelp string, params []*model.FunctionParameter) string {
 	var sb strings.Builder
 
+	if receiver != nil {
+		funcName = receiver.Declaration() + " " + funcName
+	}
 	fmt.Fprintf(&sb, "// %s is a subcommand `%s` %s\n", funcName, commandSeq, description)
 
+	// Fields of a struct parameter are configured by the struct's own field comments.
+	params = slices.DeleteFunc(slices.Clone(params), func(p *model.FunctionParameter) bool {
+		return strings.Contains(p.Name, ".")
+	})
+
 	// Extended Help
 	if extendedHelp != "" {
 		// Ensure extended help lines are commented
@@ -135,6 +144,11 @@ func generateDocComment(funcName, commandSeq, description, extendedHelp string,
 		}
 	}
 
+	if receiver != nil && receiver.Constructor != "" {
+		sb.WriteString("//\n")
+		fmt.Fprintf(&sb, "// Receiver: %s\n", receiver.Constructor)
+	}
+
 	if len(params) > 0 {
 		sb.WriteString("//\n")
 		sb.WriteString("// Flags:\n")
@@ -240,6 +240,7 @@ func (w *CollectingFileWriter) Commit(writer FileWriter) error {
 //
 //	dir:		--dir		(default: ".")		Project root directory containing go.mod
 //	manDir:		--man-dir				Directory to generate man pages in optional
+//	completionDir:	--completion-dir			Directory to write shell completion scripts to optional
 //	parserName:	--parser-name	(default: "commentv1")	Name of the parser to use
 //	paths:		--path		(default: nil)		Paths to search for subcommands (relative to dir)
 //	recursive:	--recursive	(default: true)		Search recursively
@@ -251,7 +252,7 @@ func (w *CollectingFileWriter) Commit(writer FileWriter) error {
 //	provVersion:       --prov-version    (default: "") Overwrite provenance version
 //	provCommit:        --prov-commit     (default: "") Overwrite provenance commit
 //	provDate:          --prov-date       (default: "") Overwrite provenance date
-func Generate(dir string, manDir string, parserName string, paths []string, recursive bool, force bool, clean bool, replaceTemplates []string, projectProvenance bool, timestamp bool, provVersion string, provCommit string, provDate string) error {
+func Generate(dir string, manDir string, completionDir string, parserName string, paths []string, recursive bool, force bool, clean bool, replaceTemplates []string, projectProvenance bool, timestamp bool, provVersion string, provCommit string, provDate string) error {
 	if dir == "." {
 		if _, err := os.Stat("go.mod"); os.IsNotExist(err) {
 			if root, err := findModuleRoot("."); err == nil {
@@ -259,9 +260,10 @@ func Generate(dir string, manDir string, parserName string, paths []string, recu
 			}
 		}
 	}
-	return GenerateWithFS(os.DirFS(dir), &OSFileWriter{}, dir, manDir, parserName, &parsers.ParseOptions{
+	return GenerateWithFS(os.DirFS(dir), &OSFileWriter{}, dir, manDir, completionDir, parserName, &parsers.ParseOptions{
 		SearchPaths: paths,
 		Recursive:   recursive,
+		Dir:         dir,
 	}, force, clean, replaceTemplates, projectProvenance, timestamp, provVersion, provCommit, provDate)
 }
 
@@ -282,12 +284,15 @@ func findModuleRoot(path string) (string, error) {
 	}
 }
 
-// CleanGeneratedFiles removes generated files in the cmd/ directory and manDir safely.
-func CleanGeneratedFiles(dir string, manDir string) error {
+// CleanGeneratedFiles removes generated files in the cmd/ directory, manDir and completionDir safely.
+func CleanGeneratedFiles(dir string, manDir string, completionDir string) error {
 	targets := []string{filepath.Join(dir, "cmd")}
 	if manDir != "" {
 		targets = append(targets, manDir)
 	}
+	if completionDir != "" {
+		targets = append(targets, completionDir)
+	}
 
 	for _, target := range targets {
 		if _, err := os.Stat(target); os.IsNotExist(err) {
@@ -386,9 +391,9 @@ func GetProvenance(replaceTemplates []string, projectProvenance bool, timestamp
 }
 
 // GenerateWithFS generates code using provided FS and Writer. Optional variadic args ops can provide custom dependencies such as readFS (fs.FS).
-func GenerateWithFS(inputFS fs.FS, writer FileWriter, dir string, manDir string, parserName string, options *parsers.ParseOptions, force bool, clean bool, replaceTemplates []string, projectProvenance bool, timestamp bool, provVersion string, provCommit string, provDate string, ops ...any) error {
+func GenerateWithFS(inputFS fs.FS, writer FileWriter, dir string, manDir string, completionDir string, parserName string, options *parsers.ParseOptions, force bool, clean bool, replaceTemplates []string, projectProvenance bool, timestamp bool, provVersion string, provCommit string, provDate string, ops ...any) error {
 	if clean {
-		if err := CleanGeneratedFiles(dir, manDir); err != nil {
+		if err := CleanGeneratedFiles(dir, manDir, completionDir); err != nil {
 			return fmt.Errorf("failed to clean generated files: %w", err)
 		}
 	}
@@ -472,6 +477,8 @@ func GenerateWithFS(inputFS fs.FS, writer FileWriter, dir string, manDir string,
 			Parameters:             cmd.Parameters,
 			ReturnsError:           cmd.ReturnsError,
 			ReturnCount:            cmd.ReturnCount,
+			FlagGroups:             cmd.FlagGroups,
+			Examples:               cmd.Examples,
 			UsageFileName:          fmt.Sprintf("%s_usage.txt", strings.ToLower(cmd.MainCmdName)),
 			SubCommandPackageName:  cmd.CommandPackageName,
 			SubCommandStructName:   "RootCmd",
@@ -485,6 +492,51 @@ func GenerateWithFS(inputFS fs.FS, writer FileWriter, dir string, manDir string,
 				return err
 			}
 		}
+		if cmd.HasCompletionCommand() {
+			if err := generateFile(collector, cmdOutDir, supportFileName("completion"), "completion.go.gotmpl", cmd, true); err != nil {
+				return err
+			}
+		}
+		if err := generateFile(collector, cmdOutDir, supportFileName("suggest"), "suggest.go.gotmpl", cmd, true); err != nil {
+			return err
+		}
+		if cmd.HasConfigFile() {
+			if err := generateFile(collector, cmdOutDir, supportFileName("config"), "config.go.gotmpl", cmd, true); err != nil {
+				return err
+			}
+		}
+		if cmd.HasOutput() {
+			if err := generateFile(collector, cmdOutDir, supportFileName("output"), "output.go.gotmpl", cmd, true); err != nil {
+				return err
+			}
+		}
+		if cmd.UsesPrompt() {
+			// The terminal is handled per platform, selected by file name and build tags.
+			for _, name := range []string{"prompt", "prompt_unix", "prompt_linux", "prompt_bsd", "prompt_windows", "prompt_other"} {
+				if err := generateFile(collector, cmdOutDir, supportFileName(name), name+".go.gotmpl", cmd, true); err != nil {
+					return err
+				}
+			}
+		}
+		if cmd.UsesSecretFlags() {
+			if err := generateFile(collector, cmdOutDir, supportFileName("secret"), "secret.go.gotmpl", cmd, true); err != nil {
+				return err
+			}
+		}
+		if manDir != "" {
+			for _, topic := range cmd.HelpTopics {
+				// Help topics are not commands; like git's guides they go in section 7.
+				manFileName := strings.TrimSuffix(sanitizeManFileName(cmd.MainCmdName, topic.Name), ".1") + ".7"
+				if err := generateFile(collector, manDir, manFileName, "topic.gotmpl", topic, false); err != nil {
+					return err
+				}
+			}
+		}
+		if completionDir != "" {
+			if err := generateCompletionFiles(collector, completionDir, cmd); err != nil {
+				return err
+			}
+		}
 	}
 
 	if err := collector.Verify(writer, force); err != nil {
@@ -540,8 +592,28 @@ func sanitizeManFileName(mainCmdName, subCmdSequence string) string {
 	return fmt.Sprintf("%s-%s.1", safeMain, safeSeq)
 }
 
-func generateSubCommandFiles(writer FileWriter, cmdOutDir, cmdTemplatesDir, manDir string, subCmd *model.SubCommand) error {
+// supportFilePrefix starts the names of the files of support code generated next to
+// the command files, such as the completion command.
+const supportFilePrefix = "gosubc_"
+
+// supportFileName is the name of the generated support file for name.
+func supportFileName(name string) string {
+	return supportFilePrefix + name + ".go"
+}
+
+// subCommandFileName is the base name of the files generated for a subcommand, derived
+// from its struct name. A name that would start with supportFilePrefix is prefixed once
+// more, with "cmd_" added, so that it can never be the name of a support file.
+func subCommandFileName(subCmd *model.SubCommand) string {
 	fileName := strings.ReplaceAll(parsers.ToKebabCase(subCmd.SubCommandStructName), "-", "_")
+	if strings.HasPrefix(fileName, supportFilePrefix) {
+		fileName = supportFilePrefix + "cmd_" + fileName
+	}
+	return fileName
+}
+
+func generateSubCommandFiles(writer FileWriter, cmdOutDir, cmdTemplatesDir, manDir string, subCmd *model.SubCommand) error {
+	fileName := subCommandFileName(subCmd)
 	if err := generateFile(writer, cmdOutDir, fileName+".go", "cmd.go.gotmpl", subCmd, true); err != nil {
 		return err
 	}
@@ -551,7 +623,7 @@ func generateSubCommandFiles(writer FileWriter, cmdOutDir, cmdTemplatesDir, manD
 	if err := generateFile(writer, cmdTemplatesDir, subCmd.UsageFileName, "usage.txt.gotmpl", subCmd, false); err != nil {
 		return err
 	}
-	if manDir != "" {
+	if manDir != "" && !subCmd.IsHidden() {
 		manFileName := sanitizeManFileName(subCmd.MainCmdName, subCmd.SubCommandSequence())
 		if err := generateFile(writer, manDir, manFileName, "man.gotmpl", subCmd, false); err != nil {
 			return err
@@ -565,6 +637,28 @@ func generateSubCommandFiles(writer FileWriter, cmdOutDir, cmdTemplatesDir, manD
 	return nil
 }
 
+// completionFileNames maps each completion template to the file name, relative to the
+// completion directory, that the shell expects to find it under.
+var completionFileNames = []struct {
+	Template string
+	Format   string
+}{
+	{Template: "bash.gotmpl", Format: "%s.bash"},
+	{Template: "zsh.gotmpl", Format: "_%s"},
+	{Template: "fish.gotmpl", Format: "%s.fish"},
+	{Template: "powershell.gotmpl", Format: "%s.ps1"},
+}
+
+func generateCompletionFiles(writer FileWriter, completionDir string, cmd *model.Command) error {
+	safeMain := filepath.Base(cmd.MainCmdName)
+	for _, f := range completionFileNames {
+		if err := generateFile(writer, completionDir, fmt.Sprintf(f.Format, safeMain), f.Template, cmd, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Helper to bridge legacy parse calls
 // Helper to bridge legacy parse calls
 func parse(dir string, parserName string, options *parsers.ParseOptions, ops ...any) (*model.DataModel, error) {
@@ -580,6 +674,9 @@ func parse(dir string, parserName string, options *parsers.ParseOptions, ops ...
 	}
 	if fsys == nil {
 		fsys = os.DirFS(dir)
+		if options != nil {
+			options.Dir = dir
+		}
 	}
 	p, err := parsers.Get(parserName)
 	if err != nil {
@@ -623,6 +720,27 @@ func ParseTemplates(fsys fs.FS) (*template.Template, error) {
 		},
 		"base":                filepath.Base,
 		"isDefaultExpression": isDefaultExpression,
+		"shellQuote": func(s string) string {
+			return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+		},
+		"fishQuote": func(s string) string {
+			return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+		},
+		"pwshQuote": func(s string) string {
+			return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+		},
+		"zshEscape": func(s string) string {
+			return strings.ReplaceAll(s, ":", `\:`)
+		},
+	})
+	tmpl.Funcs(template.FuncMap{
+		"include": func(name string, data interface{}) (string, error) {
+			var buf bytes.Buffer
+			if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+				return "", err
+			}
+			return buf.String(), nil
+		},
 	})
 
 	var patterns []string
@@ -674,6 +792,10 @@ func parameterImportsExcept(params []*model.FunctionParameter, excludedPath stri
 		imports = append(imports, templateImport{Alias: alias, Path: ref.ImportPath})
 	}
 	for _, p := range params {
+		for _, importPath := range p.TypeImports() {
+			add(&model.FuncRef{ImportPath: importPath})
+		}
+		add(p.TypePackage)
 		if p.Parser.Type == model.ParserTypeCustom {
 			add(p.Parser.Func)
 		}
@@ -714,7 +836,11 @@ func parameterImportsExcept(params []*model.FunctionParameter, excludedPath stri
 					// Wait, the review says: "The patch assumes the selector prefix is the full import path... The instructions explicitly required resolving the source package and import aliases from Go AST and package information".
 
 					// Let's pass the real import path in the model, or resolve it here.
-					add(&model.FuncRef{ImportPath: pkgName, CommandPackageName: pkgName})
+					importPath := pkgName
+					if stdlibPath := model.StdlibPackagePath(pkgName); stdlibPath != "" {
+						importPath = stdlibPath
+					}
+					add(&model.FuncRef{ImportPath: importPath, CommandPackageName: pkgName})
 				}
 			}
 		}
@@ -776,13 +902,16 @@ func deduplicateAndSortImports(imports []templateImport) []templateImport {
 }
 
 func subCommandImports(cmd *model.SubCommand, excludedPath string) []templateImport {
-	imports := parameterImportsExcept(cmd.Parameters, excludedPath)
+	imports := parameterImportsExcept(cmd.ParsedParameters(), excludedPath)
 	if cmd.ImportPath != "" && (cmd.ImportPath != excludedPath || (cmd.HasAction() && cmd.CallPackage() != "")) {
 		alias := cmd.ImportAlias()
 		if cmd.HasAction() || cmd.CallPackage() != "" {
 			imports = append(imports, templateImport{Alias: alias, Path: cmd.ImportPath})
 		}
 	}
+	for _, h := range cmd.HookPackages() {
+		imports = append(imports, templateImport{Alias: h.ImportAlias(), Path: h.ImportPath})
+	}
 	return deduplicateAndSortImports(imports)
 }
 
@@ -794,6 +923,12 @@ func commandImports(cmd *model.Command, excludedPath string) []templateImport {
 			imports = append(imports, templateImport{Alias: alias, Path: cmd.ImportPath})
 		}
 	}
+	for _, h := range cmd.HookPackages() {
+		imports = append(imports, templateImport{Alias: h.ImportAlias(), Path: h.ImportPath})
+	}
+	for _, e := range cmd.ExitCodePackages() {
+		imports = append(imports, templateImport{Alias: e.ImportAlias(), Path: e.ImportPath})
+	}
 	return deduplicateAndSortImports(imports)
 }
 
@@ -10,6 +10,7 @@ import (
 	"testing/fstest"
 
 	"github.com/arran4/go-subcommand/parsers"
+	"golang.org/x/tools/txtar"
 )
 
 //go:embed testdata/issue_runtime.go
@@ -36,7 +37,7 @@ func Sub() {}
 
 	// Test recursive=true (default)
 	writer := NewCollectingFileWriter()
-	err := GenerateWithFS(fs, writer, ".", "", "commentv1", &parsers.ParseOptions{Recursive: true}, false, false, nil, false, false, "", "", "")
+	err := GenerateWithFS(fs, writer, ".", "", "", "commentv1", &parsers.ParseOptions{Recursive: true}, false, false, nil, false, false, "", "", "")
 	if err != nil {
 		t.Fatalf("Generate failed: %v", err)
 	}
@@ -46,7 +47,7 @@ func Sub() {}
 
 	// Test recursive=false
 	writer = NewCollectingFileWriter()
-	err = GenerateWithFS(fs, writer, ".", "", "commentv1", &parsers.ParseOptions{Recursive: false}, false, false, nil, false, false, "", "", "")
+	err = GenerateWithFS(fs, writer, ".", "", "", "commentv1", &parsers.ParseOptions{Recursive: false}, false, false, nil, false, false, "", "", "")
 	if err != nil {
 		t.Fatalf("Generate failed: %v", err)
 	}
@@ -74,7 +75,7 @@ func Cmd2() {}
 
 	// Test with specific path
 	writer := NewCollectingFileWriter()
-	err := GenerateWithFS(fs, writer, ".", "", "commentv1", &parsers.ParseOptions{
+	err := GenerateWithFS(fs, writer, ".", "", "", "commentv1", &parsers.ParseOptions{
 		SearchPaths: []string{"pkg1"},
 		Recursive:   true,
 	}, false, false, nil, false, false, "", "", "")
@@ -96,13 +97,73 @@ func TestGenerate_RuntimeRequirements(t *testing.T) {
 	writeRuntimeFixture(t, filepath.Join(dir, "app.go"), issueRuntimeSource)
 	writeRuntimeFixture(t, filepath.Join(dir, "parserpkg", "parser.go"), issueRuntimeParserSource)
 
-	if err := Generate(dir, "", "commentv1", nil, true, true, false, nil, false, false, "", "", ""); err != nil {
+	if err := Generate(dir, "", "", "commentv1", nil, true, true, false, nil, false, false, "", "", ""); err != nil {
 		t.Fatalf("Generate failed: %v", err)
 	}
 	writeRuntimeFixture(t, filepath.Join(dir, "cmd", "app", "runtime_test.go"), issueRuntimeTestSource)
 
-	cmd := exec.Command("go", "test", "./...")
+	runGeneratedTests(t, dir)
+}
 
+// TestGenerate_RuntimeFixtures generates each testdata/runtime archive and runs the tests
+// it carries under cmd/ against the generated CLI.
+func TestGenerate_RuntimeFixtures(t *testing.T) {
+	archives, err := filepath.Glob(filepath.Join("testdata", "runtime", "*.txtar"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, archive := range archives {
+		t.Run(strings.TrimSuffix(filepath.Base(archive), ".txtar"), func(t *testing.T) {
+			t.Parallel()
+			ar, err := txtar.ParseFile(archive)
+			if err != nil {
+				t.Fatal(err)
+			}
+			dir := t.TempDir()
+			writeRuntimeFixture(t, filepath.Join(dir, "go.mod"), "module example.com/e2e\n\ngo 1.22\n")
+			var generatedTests []txtar.File
+			for _, f := range ar.Files {
+				if strings.HasPrefix(f.Name, "cmd/") {
+					generatedTests = append(generatedTests, f)
+					continue
+				}
+				writeRuntimeFixture(t, filepath.Join(dir, filepath.FromSlash(f.Name)), string(f.Data))
+			}
+			if err := Generate(dir, "", "", "commentv1", nil, true, true, false, nil, false, false, "", "", ""); err != nil {
+				t.Fatalf("Generate failed: %v", err)
+			}
+			for _, f := range generatedTests {
+				writeRuntimeFixture(t, filepath.Join(dir, filepath.FromSlash(f.Name)), string(f.Data))
+			}
+			runGeneratedTests(t, dir)
+		})
+	}
+}
+
+func TestGenerate_UnknownConversion(t *testing.T) {
+	dir := t.TempDir()
+	writeRuntimeFixture(t, filepath.Join(dir, "go.mod"), "module example.com/e2e\n\ngo 1.22\n")
+	writeRuntimeFixture(t, filepath.Join(dir, "app.go"), `package app
+
+type Mode int
+
+// App is a subcommand `+"`app`"+`
+func App(mode Mode) {}
+`)
+	err := Generate(dir, "", "", "commentv1", nil, true, true, false, nil, false, false, "", "", "")
+	if err == nil {
+		t.Fatal("Generate succeeded for a parameter type without a conversion")
+	}
+	for _, want := range []string{"app.go:6:10", "parameter mode of App", "no known conversion", "Mode"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should contain %q", err, want)
+		}
+	}
+}
+
+func runGeneratedTests(t *testing.T, dir string) {
+	t.Helper()
+	cmd := exec.Command("go", "test", "./...")
 	cmd.Dir = dir
 	output, err := cmd.CombinedOutput()
 	if err != nil {
@@ -135,7 +196,7 @@ func Root() {}
 	}
 
 	writer := NewCollectingFileWriter()
-	err := GenerateWithFS(fs, writer, ".", "", "commentv1", &parsers.ParseOptions{Recursive: true}, false, false, []string{"usage=custom_usage.gotmpl"}, false, false, "", "", "", fs)
+	err := GenerateWithFS(fs, writer, ".", "", "", "commentv1", &parsers.ParseOptions{Recursive: true}, false, false, []string{"usage=custom_usage.gotmpl"}, false, false, "", "", "", fs)
 	if err != nil {
 		t.Fatalf("GenerateWithFS with replaceTemplates failed: %v", err)
 	}
@@ -247,7 +308,7 @@ func Root(cores int, limit int) {}
 	}
 
 	writer := NewCollectingFileWriter()
-	err := GenerateWithFS(fsys, writer, ".", "", "commentv1", &parsers.ParseOptions{Recursive: true}, false, false, nil, false, false, "", "", "")
+	err := GenerateWithFS(fsys, writer, ".", "", "", "commentv1", &parsers.ParseOptions{Recursive: true}, false, false, nil, false, false, "", "", "")
 	if err != nil {
 		t.Fatalf("GenerateWithFS failed: %v", err)
 	}
@@ -278,14 +339,14 @@ func TestGenerate_Clean(t *testing.T) {
 	writeRuntimeFixture(t, filepath.Join(dir, "app.go"), issueRuntimeSource)
 	writeRuntimeFixture(t, filepath.Join(dir, "parserpkg", "parser.go"), issueRuntimeParserSource)
 
-	if err := Generate(dir, "", "commentv1", nil, true, true, false, nil, false, false, "", "", ""); err != nil {
+	if err := Generate(dir, "", "", "commentv1", nil, true, true, false, nil, false, false, "", "", ""); err != nil {
 		t.Fatalf("Generate failed: %v", err)
 	}
 
 	customFile := filepath.Join(dir, "cmd", "app", "custom.go")
 	writeRuntimeFixture(t, customFile, "package app\n// Custom user file\n")
 
-	if err := Generate(dir, "", "commentv1", nil, true, true, true, nil, false, false, "", "", ""); err != nil {
+	if err := Generate(dir, "", "", "commentv1", nil, true, true, true, nil, false, false, "", "", ""); err != nil {
 		t.Fatalf("Generate with clean failed: %v", err)
 	}
 
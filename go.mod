@@ -9,3 +9,5 @@ require golang.org/x/tools v0.48.0
 require golang.org/x/text v0.32.0
 
 require github.com/arran4/strings2 v0.0.6
+
+require golang.org/x/sync v0.22.0 // indirect
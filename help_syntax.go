@@ -20,10 +20,30 @@ Command Definition:
     // MyCommand is a subcommand my-cmd sub-cmd that does something
     func MyCommand(arg string) error { ... }
 
+  Methods work too. A 'Receiver: <Constructor>' line names the function that
+  creates the receiver; without it the method runs on the type's zero value.
+
+    // (s *Server) Start is a subcommand my-cmd start that starts the server
+    // Receiver: NewServer
+    func (s *Server) Start() error { ... }
+
+  A command returning (T, error) has T written to stdout in the format given with
+  the root '--output text|json|jsonl|table' flag.
+
 Arguments / Flags:
 
   Arguments are defined by function parameters.
   Supported types: string, int, int64, bool, float64, time.Duration.
+  Maps such as map[string]string are set with repeated key=value flags; a default
+  is written as (default: {key: value, other: value}).
+  time.Time, *url.URL, net.IP, netip.Addr, netip.Prefix, *regexp.Regexp, []byte
+  and *big.Int are parsed too. Use '(layout: 2006-01-02)' for a time.Time, and
+  '(encoding: hex)' for a []byte written as hex rather than base64.
+  Any other type implementing encoding.TextUnmarshaler or flag.Value is parsed with
+  UnmarshalText or Set; a type with neither fails generation.
+  A context.Context parameter is never a flag; it receives the command's context.
+  An io.Reader marked (stdin) or io.Writer marked (stdout) or (stderr) receives that stream.
+  A struct parameter becomes one flag per exported field, configured by field comments.
 
   You can customize flags using:
   1. Flags Block (Recommended):
@@ -68,6 +88,94 @@ Defaults:
 
   // arg: --arg (default: "default value")
 
+Choices:
+
+  Use '(choices: a, b)' to reject any value that is not listed.
+
+  // format: --format (choices: json, yaml) (default: "json")
+
+Value Checks:
+
+  Use '(min: 1; max: 10)' on numbers and durations, '(minlen: 3)', '(maxlen: 64)' and
+  '(pattern: "^[a-z]+$")' on strings, and '(nonempty)' on strings, slices and maps.
+
+  // port: --port (min: 1; max: 65535) (default: 8080)
+
+Flag Constraints:
+
+  Use '(conflicts: a)' and '(requires: b)' on a flag, and 'OneOf: a, b' or
+  'AtLeastOne: a, b' lines on a command, to check how flags are combined.
+
+  // all: --all (conflicts: name)
+
+Interspersed Flags:
+
+  Flags must come before positional arguments unless a command has a
+  'FlagParsing: interspersed' line, which its subcommands inherit.
+
+Prompting:
+
+  Use '(prompt)' or '(prompt: "Label")' to ask for a missing required value when
+  stdin is a terminal, and '(secret)' to ask without echo. Without a terminal the
+  value is reported missing as usual.
+
+  // password: --password (secret; required)
+
+Secrets:
+
+  Use '(secret)' to accept '--token @path' and '--token -', reading the value from a
+  file or stdin, and to mask the default in help.
+
+  // token: --token (secret)
+
+Persistent Flags:
+
+  Use '(persistent)' on a flag of the root or an intermediate command to accept it
+  in every subcommand below, e.g. 'app users list --verbose'.
+
+  // verbose: --verbose -v (persistent)
+
+Hidden and Deprecated:
+
+  Use '(hidden)' to leave a flag out of help, and '(deprecated: "note")' to warn
+  when it is used; '(replacement: b)' also passes its value on to flag b. 'Hidden'
+  and 'Deprecated: note' lines do the same for a command.
+
+  // format: --format (deprecated; replacement: output)
+
+Examples:
+
+  An 'Examples:' block lists indented command lines, each starting with the full
+  command path and optionally followed by a # comment. They are shown in help and
+  man pages, and the generated tests check that their arguments parse.
+
+  // Examples:
+  //
+  //	app greet --name Bob  # Greet Bob
+
+Hooks:
+
+  'Before: Func' and 'After: Func' lines run a function of the package around the
+  action of the command and of every command below it. Its parameters are passed
+  the flags of the same name, and a context.Context the command's context.
+
+  // Before: OpenDB
+
+Exit Codes:
+
+  An 'ExitCodes: Error=Code, ...' line exits with Code for errors matching a sentinel
+  error (errors.Is) or an error type written *T (errors.As). Usage errors exit with 64.
+
+  // ExitCodes: ErrNotFound=3, *ConflictError=4
+
+Help Topics:
+
+  A string constant or variable documented as a help topic is printed by
+  'app help <topic>' and listed under "Help topics" in the root usage.
+
+  // EnvHelp is a help topic 'app environment' -- Environment variables
+  const EnvHelp = "..."
+
 Implicit Parsing:
 
   If no specific flag is defined, parameter names are converted to kebab-case flags.
@@ -0,0 +1,31 @@
+package go_subcommand
+
+import (
+	"testing"
+)
+
+const helpTopicsSource = `package main
+
+// EnvHelp is a help topic 'app environment' -- Environment variables
+const EnvHelp = ` + "`" + `
+.APP_HOME sets the data directory, e.g. C:\app.
+` + "`" + `
+
+// Root is a subcommand ` + "`app`" + `
+func Root() {}
+`
+
+func TestHelpTopics_ManPage(t *testing.T) {
+	writer := NewCollectingFileWriter()
+	if err := GenerateWithFS(setupProject(t, helpTopicsSource), writer, ".", "man", "", "commentv1", nil, false, false, nil, false, false, "", "", ""); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	man := string(mustGeneratedFile(t, writer, "man/app-environment.7"))
+	assertContains(t, man, ".TH APP-ENVIRONMENT 7", "topic man pages should be in section 7")
+	assertContains(t, man, `app-environment \- Environment variables`, "the name line should carry the description")
+	assertContains(t, man, `\&.APP_HOME sets the data directory, e.g. C:\eapp.`, "the text should be escaped for roff")
+
+	usage := string(mustGeneratedFile(t, writer, "cmd/app/templates/app_usage.txt"))
+	assertContains(t, usage, "Help topics:", "root usage should list the topics")
+}
@@ -35,7 +35,7 @@ func setupProject(t *testing.T, sourceCode string) fstest.MapFS {
 func runGenerateInMemory(t *testing.T, inputFS fstest.MapFS) *CollectingFileWriter {
 	writer := NewCollectingFileWriter()
 	// We use a dummy dir name like "." or "/app"
-	if err := GenerateWithFS(inputFS, writer, ".", "", "commentv1", nil, false, false, nil, false, false, "", "", ""); err != nil {
+	if err := GenerateWithFS(inputFS, writer, ".", "", "", "commentv1", nil, false, false, nil, false, false, "", "", ""); err != nil {
 		t.Fatalf("Generate failed: %v", err)
 	}
 	return writer
@@ -55,12 +55,12 @@ func TestIssue350_388_GeneratedRequirements(t *testing.T) {
 	}
 
 	rootGo := string(mustGeneratedFile(t, writer, "cmd/app/root.go"))
-	assertContains(t, rootGo, `executeUsage(os.Stderr, "app_usage.txt"`, "root usage should use embedded usage template")
+	assertContains(t, rootGo, `executeUsage(c.IO.Stderr(), "app_usage.txt"`, "root usage should use embedded usage template")
 	assertContains(t, rootGo, `seenFlags := make(map[string]bool)`, "required root flag should create seenFlags")
 	assertContains(t, rootGo, `required flag --config not provided`, "required root flag should be validated")
 	if action := strings.Index(rootGo, "if c.CommandAction != nil"); action == -1 {
 		t.Fatal("root command action missing")
-	} else if dispatch := strings.Index(rootGo, "if !dashDashSeen && len(remainingArgs) > 0"); dispatch == -1 {
+	} else if dispatch := strings.Index(rootGo, "return cmd().ExecuteContext(ctx, remainingArgs[1:])"); dispatch == -1 {
 		t.Fatal("root command dispatch missing")
 	} else if action > dispatch {
 		t.Fatalf("root command action should run before subcommand dispatch\nroot.go:\n%s", rootGo)
@@ -212,7 +212,7 @@ func ListHeads() {}
 	fs := setupProject(t, src)
 	writer := NewCollectingFileWriter()
 
-	err := GenerateWithFS(fs, writer, ".", "", "commentv1", nil, false, false, nil, false, false, "", "", "")
+	err := GenerateWithFS(fs, writer, ".", "", "", "commentv1", nil, false, false, nil, false, false, "", "", "")
 
 	if err != nil {
 		// This test verifies that the issue is still present (OPEN).
@@ -1091,7 +1091,7 @@ func Child() {}
 
 	writer := NewCollectingFileWriter()
 	// Generate code
-	if err := GenerateWithFS(fs, writer, ".", "", "commentv1", nil, false, false, nil, false, false, "", "", ""); err != nil {
+	if err := GenerateWithFS(fs, writer, ".", "", "", "commentv1", nil, false, false, nil, false, false, "", "", ""); err != nil {
 		t.Fatalf("Generate failed: %v", err)
 	}
 
@@ -1133,7 +1133,7 @@ func MyCmd() {}
 
 	// 1. Initial generation
 	writer := NewCollectingFileWriter()
-	err := GenerateWithFS(fs, writer, ".", "", "commentv1", nil, false, false, nil, false, false, "", "", "")
+	err := GenerateWithFS(fs, writer, ".", "", "", "commentv1", nil, false, false, nil, false, false, "", "", "")
 	if err != nil {
 		t.Fatalf("Initial generation failed: %v", err)
 	}
@@ -1151,7 +1151,7 @@ func MyCmd() {}
 	writer.Files[cmdFile] = []byte("package main\n// Manual edit\nfunc MyCmd() {}")
 
 	// 3. Generate without force -> Should fail
-	err = GenerateWithFS(fs, writer, ".", "", "commentv1", nil, false, false, nil, false, false, "", "", "")
+	err = GenerateWithFS(fs, writer, ".", "", "", "commentv1", nil, false, false, nil, false, false, "", "", "")
 	if err == nil {
 		t.Errorf("Expected failure when overwriting non-generated file without force")
 	} else if !strings.Contains(err.Error(), "exists and was not generated by gosubc") {
@@ -1159,7 +1159,7 @@ func MyCmd() {}
 	}
 
 	// 4. Generate with force -> Should succeed
-	err = GenerateWithFS(fs, writer, ".", "", "commentv1", nil, true, false, nil, false, false, "", "", "")
+	err = GenerateWithFS(fs, writer, ".", "", "", "commentv1", nil, true, false, nil, false, false, "", "", "")
 	if err != nil {
 		t.Errorf("Expected success with force: %v", err)
 	}
@@ -1175,7 +1175,7 @@ func MyCmd() {}
 	writer.Files[extraFile] = []byte("I shouldn't be here")
 
 	// 6. Generate without force -> Should fail
-	err = GenerateWithFS(fs, writer, ".", "", "commentv1", nil, false, false, nil, false, false, "", "", "")
+	err = GenerateWithFS(fs, writer, ".", "", "", "commentv1", nil, false, false, nil, false, false, "", "", "")
 	if err == nil {
 		t.Errorf("Expected failure with extraneous file without force")
 	} else if !strings.Contains(err.Error(), "present in the directory but not in the generated set") {
@@ -1183,7 +1183,7 @@ func MyCmd() {}
 	}
 
 	// 7. Generate with force -> Should succeed
-	err = GenerateWithFS(fs, writer, ".", "", "commentv1", nil, true, false, nil, false, false, "", "", "")
+	err = GenerateWithFS(fs, writer, ".", "", "", "commentv1", nil, true, false, nil, false, false, "", "", "")
 	if err != nil {
 		t.Errorf("Expected success with force: %v", err)
 	}
@@ -0,0 +1,234 @@
+package model
+
+import (
+	"slices"
+	"strings"
+)
+
+// CompletionNode is a single command in the flattened tree used by the shell completion templates.
+type CompletionNode struct {
+	// Path is the space separated subcommand path below the root command ("" for the root itself).
+	Path string
+	// Commands lists the words that may follow this command.
+	Commands []CompletionCommand
+	// Flags lists the flags accepted by this command.
+	Flags []CompletionFlag
+}
+
+// CommandWords returns every command name and alias of the node separated by spaces.
+func (n *CompletionNode) CommandWords() string {
+	var words []string
+	for _, c := range n.Commands {
+		words = append(words, c.Words()...)
+	}
+	return strings.Join(words, " ")
+}
+
+// FlagWords returns every flag spelling of the node separated by spaces.
+func (n *CompletionNode) FlagWords() string {
+	var words []string
+	for _, f := range n.Flags {
+		words = append(words, f.Names...)
+	}
+	return strings.Join(words, " ")
+}
+
+// CompletionCommand is a word that moves completion from one node to another.
+type CompletionCommand struct {
+	// Name is the primary name of the subcommand.
+	Name string
+	// Aliases are the alternative names of the subcommand.
+	Aliases []string
+	// Description is the short description shown by shells that support it.
+	Description string
+	// Path is the CompletionNode.Path of the target command.
+	Path string
+}
+
+// Words returns the name followed by the aliases.
+func (c CompletionCommand) Words() []string {
+	return append([]string{c.Name}, c.Aliases...)
+}
+
+// CompletionFlag is a flag accepted by a command.
+type CompletionFlag struct {
+	// Names are the flag spellings including their leading dashes.
+	Names []string
+	// Description is the help text of the flag.
+	Description string
+	// TakesValue is true when the flag consumes a value.
+	TakesValue bool
+	// Choices lists the values offered after the flag, if the parameter restricts them.
+	Choices []string
+}
+
+// ChoiceWords returns the choices separated by spaces.
+func (f CompletionFlag) ChoiceWords() string {
+	return strings.Join(f.Choices, " ")
+}
+
+// ChoiceFlags returns the flags of the node that have a fixed set of values.
+func (n *CompletionNode) ChoiceFlags() []CompletionFlag {
+	var flags []CompletionFlag
+	for _, f := range n.Flags {
+		if len(f.Choices) > 0 {
+			flags = append(flags, f)
+		}
+	}
+	return flags
+}
+
+// LongNames returns the names that start with a double dash, without the dashes.
+func (f CompletionFlag) LongNames() []string {
+	var names []string
+	for _, n := range f.Names {
+		if strings.HasPrefix(n, "--") {
+			names = append(names, strings.TrimPrefix(n, "--"))
+		}
+	}
+	return names
+}
+
+// ShortNames returns the single character names, without the dash.
+func (f CompletionFlag) ShortNames() []string {
+	var names []string
+	for _, n := range f.Names {
+		if !strings.HasPrefix(n, "--") {
+			names = append(names, strings.TrimPrefix(n, "-"))
+		}
+	}
+	return names
+}
+
+// FlagNames returns the command line spellings of the flag, including dashes.
+// FlagAliases are used when present, otherwise the parameter name.
+func (p *FunctionParameter) FlagNames() []string {
+	names := p.FlagAliases
+	if len(names) == 0 {
+		names = []string{p.Name}
+	}
+	var out []string
+	for _, n := range names {
+		if len(n) > 1 {
+			out = append(out, "--"+n)
+		} else {
+			out = append(out, "-"+n)
+		}
+	}
+	return out
+}
+
+// CompletionFunctionName returns a shell safe identifier derived from the command name.
+func (cmd *Command) CompletionFunctionName() string {
+	var b strings.Builder
+	b.WriteString("_")
+	for _, r := range cmd.MainCmdName {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
+
+// HasCompletionCommand reports whether the generated root has the built-in completion
+// command. It gives way to a command of the same name declared by the program.
+func (cmd *Command) HasCompletionCommand() bool {
+	for _, sc := range cmd.SubCommands {
+		if strings.EqualFold(sc.SubCommandName, "completion") || slices.ContainsFunc(sc.Aliases, func(a string) bool { return strings.EqualFold(a, "completion") }) {
+			return false
+		}
+	}
+	return true
+}
+
+// CompletionNodes flattens the command tree for the completion script templates.
+// The built-in help, usage, version and completion commands are included.
+func (cmd *Command) CompletionNodes() []*CompletionNode {
+	root := &CompletionNode{
+		Commands: completionCommands("", cmd.SubCommands),
+		Flags:    completionFlags(cmd.FlagParameters()),
+	}
+	root.Commands = append(root.Commands,
+		CompletionCommand{Name: "help", Description: "Print this help message"},
+		CompletionCommand{Name: "usage", Description: "Print this usage message"},
+		CompletionCommand{Name: "version", Description: "Print version information"},
+	)
+	if cmd.HasCompletionCommand() {
+		root.Commands = append(root.Commands, CompletionCommand{Name: "completion", Description: "Print a shell completion script"})
+	}
+	nodes := []*CompletionNode{root}
+	var walk func(scs []*SubCommand)
+	walk = func(scs []*SubCommand) {
+		for _, sc := range scs {
+			if sc.Hidden {
+				continue
+			}
+			node := &CompletionNode{
+				Path:     sc.SubCommandSequence(),
+				Commands: completionCommands(sc.SubCommandSequence(), sc.SubCommands),
+				Flags:    completionFlags(sc.ParsedParameters()),
+			}
+			node.Commands = append(node.Commands,
+				CompletionCommand{Name: "help", Description: "Print this help message"},
+				CompletionCommand{Name: "usage", Description: "Print this usage message"},
+			)
+			nodes = append(nodes, node)
+			walk(sc.SubCommands)
+		}
+	}
+	walk(cmd.SubCommands)
+	return nodes
+}
+
+func completionCommands(parentPath string, scs []*SubCommand) []CompletionCommand {
+	var commands []CompletionCommand
+	for _, sc := range scs {
+		if sc.Hidden {
+			continue
+		}
+		p := sc.SubCommandName
+		if parentPath != "" {
+			p = parentPath + " " + p
+		}
+		commands = append(commands, CompletionCommand{
+			Name:        strings.ToLower(sc.SubCommandName),
+			Aliases:     lowerAll(sc.Aliases),
+			Description: sc.SubCommandDescription,
+			Path:        p,
+		})
+	}
+	return commands
+}
+
+// completionFlags lists the flags a command parses. The persistent flags of ancestors
+// are part of params; a parameter taken from a parent is left out, as the command reads
+// it through the flag of that parent rather than parsing it itself.
+func completionFlags(params []*FunctionParameter) []CompletionFlag {
+	var flags []CompletionFlag
+	for _, p := range params {
+		if p.IsPositional || p.HasGenerator() || p.InheritedFrom != "" || p.Hidden {
+			continue
+		}
+		flags = append(flags, CompletionFlag{
+			Names:       p.FlagNames(),
+			Description: p.Description,
+			TakesValue:  !p.IsBool(),
+			Choices:     p.Choices,
+		})
+	}
+	flags = append(flags, CompletionFlag{
+		Names:       []string{"--help", "-h"},
+		Description: "Print this help message",
+	})
+	return flags
+}
+
+func lowerAll(in []string) []string {
+	var out []string
+	for _, s := range in {
+		out = append(out, strings.ToLower(s))
+	}
+	return out
+}
@@ -0,0 +1,99 @@
+package model
+
+import (
+	"slices"
+	"strings"
+)
+
+// Config file formats understood by the generated loader.
+const (
+	ConfigFormatJSON = "json"
+	ConfigFormatINI  = "ini"
+)
+
+// ConfigFlagName is the root flag that names the config file to load.
+const ConfigFlagName = "config"
+
+// HasConfigFile reports whether the generated root command accepts a --config file.
+func (cmd *Command) HasConfigFile() bool {
+	return cmd != nil && len(cmd.ConfigFormats) > 0
+}
+
+// ConfigSupports reports whether format is one of the enabled config file formats.
+func (cmd *Command) ConfigSupports(format string) bool {
+	return cmd.HasConfigFile() && slices.Contains(cmd.ConfigFormats, format)
+}
+
+// ConfigParameter describes the --config flag so it can be listed alongside the
+// declared root flags. It is nil when no config file support was requested.
+func (cmd *Command) ConfigParameter() *FunctionParameter {
+	if !cmd.HasConfigFile() {
+		return nil
+	}
+	description := "Load flag values from a JSON file"
+	if cmd.ConfigSupports(ConfigFormatINI) {
+		if cmd.ConfigSupports(ConfigFormatJSON) {
+			description = "Load flag values from a JSON or INI file"
+		} else {
+			description = "Load flag values from an INI file"
+		}
+	}
+	p := &FunctionParameter{
+		Name:        ConfigFlagName,
+		Type:        "string",
+		Description: description,
+		DeclaredIn:  cmd.MainCmdName,
+	}
+	if cmd.EnvPrefix != "" {
+		p.Env = EnvVarName(cmd.EnvPrefix, ConfigFlagName)
+	}
+	return p
+}
+
+// FlagParameters returns the root parameters followed by the --config and --output
+// flags when enabled.
+func (cmd *Command) FlagParameters() []*FunctionParameter {
+	params := cmd.Parameters
+	for _, p := range []*FunctionParameter{cmd.ConfigParameter(), cmd.OutputParameter()} {
+		if p != nil {
+			params = append(slices.Clip(params), p)
+		}
+	}
+	return params
+}
+
+// ResolveConfig assigns every flag the key it is read from in a config file: the
+// lower-cased subcommand path joined by dots, followed by the flag name, for example
+// "users.create.name". Root flags use the bare flag name.
+func (cmd *Command) ResolveConfig() {
+	if !cmd.HasConfigFile() {
+		return
+	}
+	resolveParameterConfig(cmd.Parameters, cmd.MainCmdName, "")
+	for _, sc := range cmd.SubCommands {
+		sc.resolveConfig("")
+	}
+}
+
+func (sc *SubCommand) resolveConfig(parentKey string) {
+	key := strings.ToLower(sc.SubCommandName)
+	if parentKey != "" {
+		key = parentKey + "." + key
+	}
+	resolveParameterConfig(sc.Parameters, sc.SubCommandName, key)
+	for _, child := range sc.SubCommands {
+		child.resolveConfig(key)
+	}
+}
+
+func resolveParameterConfig(params []*FunctionParameter, cmdName, key string) {
+	for _, p := range params {
+		if p.IsPositional || p.HasGenerator() || (p.DeclaredIn != "" && p.DeclaredIn != cmdName) {
+			continue
+		}
+		p.ConfigKey = strings.TrimLeft(p.PrimaryFlagName(), "-")
+		if key != "" {
+			p.ConfigKey = key + "." + p.ConfigKey
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Kinds of flag group.
+const (
+	// FlagGroupOneOf requires exactly one flag of the group to be given.
+	FlagGroupOneOf = "OneOf"
+	// FlagGroupAtLeastOne requires one or more flags of the group to be given.
+	FlagGroupAtLeastOne = "AtLeastOne"
+)
+
+// FlagGroup is a set of flags of one command that a OneOf or AtLeastOne directive
+// constrains together.
+type FlagGroup struct {
+	// Kind is FlagGroupOneOf or FlagGroupAtLeastOne.
+	Kind string
+	// Names are the members of the group as written, by parameter or flag name.
+	Names []string
+	// Parameters are the parameters Names refer to. They are set by validation.
+	Parameters []*FunctionParameter `json:"-"`
+}
+
+// Summary describes the group for usage output and errors, e.g. "exactly one of --json, --yaml".
+func (g *FlagGroup) Summary() string {
+	quantity := "exactly one of"
+	if g.Kind == FlagGroupAtLeastOne {
+		quantity = "at least one of"
+	}
+	return quantity + " " + flagList(g.Parameters)
+}
+
+func flagList(params []*FunctionParameter) string {
+	names := make([]string, len(params))
+	for i, p := range params {
+		names[i] = p.PrimaryFlagName()
+	}
+	return strings.Join(names, ", ")
+}
+
+// ConflictsString lists the primary names of the flags this one cannot be given with,
+// whichever of the two declared the conflict.
+func (p *FunctionParameter) ConflictsString() string {
+	return flagList(append(slices.Clip(p.ConflictParams), p.conflictedBy...))
+}
+
+// RequiresString lists the primary names of the flags this one needs.
+func (p *FunctionParameter) RequiresString() string {
+	return flagList(p.RequiredParams)
+}
+
+// HasFlagConstraints reports whether the generated Execute checks conflicts, requires or
+// flag groups, which only count the flags given on the command line.
+func (cmd *Command) HasFlagConstraints() bool {
+	return slices.ContainsFunc(cmd.Parameters, func(p *FunctionParameter) bool { return p.constrained })
+}
+
+// HasFlagConstraints reports whether the generated Execute checks conflicts, requires or
+// flag groups, which only count the flags given on the command line.
+func (sc *SubCommand) HasFlagConstraints() bool {
+	return slices.ContainsFunc(sc.Parameters, func(p *FunctionParameter) bool { return p.constrained })
+}
+
+// lookupParameter finds the parameter a reference names, by parameter name first and
+// then by flag alias.
+func lookupParameter(params []*FunctionParameter, ref string) *FunctionParameter {
+	for _, p := range params {
+		if p.Name == ref {
+			return p
+		}
+	}
+	for _, p := range params {
+		if slices.Contains(p.FlagAliases, ref) {
+			return p
+		}
+	}
+	return nil
+}
+
+// resolveFlagConstraints checks that the conflicts, requires and flag groups of a command
+// refer to its own flags, and links each reference to the parameter it names.
+func resolveFlagConstraints(params []*FunctionParameter, groups []*FlagGroup, cmdName string) error {
+	lookup := func(ref string) *FunctionParameter {
+		return lookupParameter(params, ref)
+	}
+	resolve := func(ref, context string) (*FunctionParameter, error) {
+		p := lookup(ref)
+		if p == nil {
+			return nil, fmt.Errorf("command %s: %s refers to unknown flag %s", cmdName, context, ref)
+		}
+		if p.IsPositional || p.IsVarArg || p.HasGenerator() || p.InheritedFrom != "" {
+			return nil, fmt.Errorf("command %s: %s refers to %s, which is not a flag of this command", cmdName, context, ref)
+		}
+		return p, nil
+	}
+	for _, p := range params {
+		p.ConflictParams, p.RequiredParams, p.conflictedBy = nil, nil, nil
+	}
+	for _, p := range params {
+		if len(p.Conflicts) == 0 && len(p.Requires) == 0 {
+			continue
+		}
+		if p.IsPositional || p.IsVarArg || p.HasGenerator() {
+			return fmt.Errorf("command %s: only flags can conflict with or require other flags, not %s", cmdName, p.Name)
+		}
+		for _, ref := range p.Conflicts {
+			other, err := resolve(ref, "parameter "+p.Name+" conflicts")
+			if err != nil {
+				return err
+			}
+			if other == p {
+				return fmt.Errorf("command %s: parameter %s cannot conflict with itself", cmdName, p.Name)
+			}
+			if !slices.Contains(other.ConflictParams, p) && !slices.Contains(p.ConflictParams, other) {
+				p.ConflictParams = append(p.ConflictParams, other)
+				other.conflictedBy = append(other.conflictedBy, p)
+			}
+			other.constrained, p.constrained = true, true
+		}
+		for _, ref := range p.Requires {
+			other, err := resolve(ref, "parameter "+p.Name+" requires")
+			if err != nil {
+				return err
+			}
+			if other == p {
+				return fmt.Errorf("command %s: parameter %s cannot require itself", cmdName, p.Name)
+			}
+			if slices.ContainsFunc(p.Conflicts, func(ref string) bool { return lookup(ref) == other }) {
+				return fmt.Errorf("command %s: parameter %s both requires and conflicts with %s", cmdName, p.Name, ref)
+			}
+			if !slices.Contains(p.RequiredParams, other) {
+				p.RequiredParams = append(p.RequiredParams, other)
+			}
+			other.constrained, p.constrained = true, true
+		}
+	}
+	for _, g := range groups {
+		if g.Kind != FlagGroupOneOf && g.Kind != FlagGroupAtLeastOne {
+			return fmt.Errorf("command %s: unknown flag group kind %q", cmdName, g.Kind)
+		}
+		g.Parameters = nil
+		for _, ref := range g.Names {
+			p, err := resolve(ref, g.Kind)
+			if err != nil {
+				return err
+			}
+			if slices.Contains(g.Parameters, p) {
+				return fmt.Errorf("command %s: %s lists %s twice", cmdName, g.Kind, ref)
+			}
+			p.constrained = true
+			g.Parameters = append(g.Parameters, p)
+		}
+		if len(g.Parameters) < 2 {
+			return fmt.Errorf("command %s: %s needs at least two flags, got %d", cmdName, g.Kind, len(g.Parameters))
+		}
+	}
+	return nil
+}
+
+// sampleOmissions picks the flags the generated tests leave out of the invocation they
+// run, which otherwise passes every flag, so that it meets the flag constraints.
+func sampleOmissions(params []*FunctionParameter, groups []*FlagGroup) map[*FunctionParameter]bool {
+	omit := map[*FunctionParameter]bool{}
+	for _, p := range params {
+		// The generated tests have no sample value for a converted type.
+		if p.Conversion != "" {
+			omit[p] = true
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		drop := func(p *FunctionParameter) {
+			omit[p] = true
+			changed = true
+		}
+		for _, p := range params {
+			for _, other := range p.ConflictParams {
+				if omit[p] || omit[other] {
+					continue
+				}
+				if other.Required && !p.Required {
+					drop(p)
+				} else {
+					drop(other)
+				}
+			}
+			for _, other := range p.RequiredParams {
+				if !omit[p] && omit[other] {
+					drop(p)
+				}
+			}
+		}
+		for _, g := range groups {
+			if g.Kind != FlagGroupOneOf {
+				continue
+			}
+			kept := false
+			for _, p := range g.Parameters {
+				switch {
+				case omit[p]:
+				case kept:
+					drop(p)
+				default:
+					kept = true
+				}
+			}
+		}
+	}
+	return omit
+}
+
+// SampleOmits reports whether the generated tests leave the flag out of the invocation
+// they run to meet the command's flag constraints.
+func (cmd *Command) SampleOmits(p *FunctionParameter) bool {
+	return sampleOmissions(cmd.Parameters, cmd.FlagGroups)[p]
+}
+
+// SampleOmits reports whether the generated tests leave the flag out of the invocation
+// they run to meet the subcommand's flag constraints.
+func (sc *SubCommand) SampleOmits(p *FunctionParameter) bool {
+	return sampleOmissions(sc.Parameters, sc.FlagGroups)[p]
+}
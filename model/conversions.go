@@ -0,0 +1,83 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Methods through which a parameter type converts itself from a command line value.
+const (
+	// ConversionUnmarshalText parses with encoding.TextUnmarshaler's UnmarshalText.
+	ConversionUnmarshalText = "UnmarshalText"
+	// ConversionSet parses with flag.Value's Set.
+	ConversionSet = "Set"
+)
+
+// HasTypedParser reports whether ParserCall returns a value of the parameter's own
+// type, ready to assign, as it does for the supported standard library types and for
+// types with a Conversion method.
+func (p *FunctionParameter) HasTypedParser() bool {
+	return p.IsStdlibType() || p.hasConversion()
+}
+
+func (p *FunctionParameter) hasConversion() bool {
+	return p.Conversion != "" && !p.HasCustomParser()
+}
+
+// conversionCall is an expression parsing value with the type's Conversion method. A
+// pointer type is allocated with new, so that pointer receivers have somewhere to write.
+// Like the flag package, Set is called on the current value of a single valued
+// parameter, read from current, so that a type can accumulate repeated flags.
+func (p *FunctionParameter) conversionCall(current, value string) string {
+	t := p.elemType()
+	if p.Conversion == ConversionSet && !p.IsSlice() {
+		init := ""
+		if strings.HasPrefix(t, "*") {
+			init = "if v == nil { v = new(" + strings.TrimPrefix(t, "*") + ") }; "
+		}
+		return fmt.Sprintf("func(v %s, s string) (%s, error) { %serr := v.Set(s); return v, err }(%s, %s)", t, t, init, current, value)
+	}
+	init := "var v " + t
+	if strings.HasPrefix(t, "*") {
+		init = "v := new(" + strings.TrimPrefix(t, "*") + ")"
+	}
+	arg := "s"
+	if p.Conversion == ConversionUnmarshalText {
+		arg = "[]byte(s)"
+	}
+	return fmt.Sprintf("func(s string) (%s, error) { %s; err := v.%s(%s); return v, err }(%s)", t, init, p.Conversion, arg, value)
+}
+
+// ParserCallOn is ParserCall for code where the parameter's current value is not
+// c.<Name>, such as the flag set definitions in a constructor.
+func (p *FunctionParameter) ParserCallOn(current, valName string) string {
+	if p.hasConversion() {
+		return p.conversionCall(current, valName)
+	}
+	return p.ParserCall(valName)
+}
+
+// conversionDescription names a converted type in messages by its unqualified name.
+func (p *FunctionParameter) conversionDescription() string {
+	t := p.BaseType()
+	return t[strings.LastIndex(t, ".")+1:]
+}
+
+// NeedsConversion reports whether the generated code has no way yet to turn a command
+// line value into the parameter's type: it is not a built-in, standard library or map
+// type, and has no custom parser, generator or Conversion.
+func (p *FunctionParameter) NeedsConversion() bool {
+	if p.HasGenerator() || p.HasCustomParser() || p.IsMap() || p.IsString() {
+		return false
+	}
+	return p.ParserCall("s") == ""
+}
+
+// validateConversion checks that a command line value can be turned into the parameter,
+// so that no parameter is generated without parsing code.
+func validateConversion(p *FunctionParameter, cmdName string) error {
+	if p.IsContext() || p.IsStream() || p.Inherited || p.InheritedFrom != "" || !p.NeedsConversion() {
+		return nil
+	}
+	return fmt.Errorf("command %s: parameter %s has type %s, which cannot be parsed from the command line; give it a parser or implement encoding.TextUnmarshaler or flag.Value", cmdName, p.Name, p.Type)
+}
@@ -0,0 +1,76 @@
+package model
+
+import "fmt"
+
+// deprecationNote is the note printed with the deprecation warning of the flag,
+// pointing at its replacement when it has one and no note of its own.
+func (p *FunctionParameter) deprecationNote() string {
+	if p.DeprecationNote == "" && p.ReplacementParam != nil {
+		return "use " + p.ReplacementParam.PrimaryFlagName() + " instead"
+	}
+	return p.DeprecationNote
+}
+
+// DeprecationWarning is the line the generated code prints to stderr when the deprecated
+// flag is given.
+func (p *FunctionParameter) DeprecationWarning() string {
+	return deprecationWarning("flag "+p.PrimaryFlagName(), p.deprecationNote())
+}
+
+// DeprecationWarning is the line the generated code prints to stderr when the deprecated
+// subcommand runs.
+func (sc *SubCommand) DeprecationWarning() string {
+	return deprecationWarning(fmt.Sprintf("command %q", sc.ProgName()), sc.DeprecationNote)
+}
+
+func deprecationWarning(subject, note string) string {
+	if note == "" {
+		return "warning: " + subject + " is deprecated"
+	}
+	return "warning: " + subject + " is deprecated: " + note
+}
+
+// IsHidden reports whether the subcommand, or a command above it, is hidden, which
+// leaves it out of man pages and completion.
+func (sc *SubCommand) IsHidden() bool {
+	for c := sc; c != nil; c = c.Parent {
+		if c.Hidden {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveDeprecations checks that only flags of a command are hidden or deprecated, and
+// links each deprecated flag to the flag of the same command and type it is replaced by.
+func resolveDeprecations(params []*FunctionParameter, cmdName string) error {
+	for _, p := range params {
+		p.ReplacementParam = nil
+		p.replaced = false
+	}
+	for _, p := range params {
+		if !p.Hidden && !p.Deprecated {
+			continue
+		}
+		if p.IsPositional || p.IsVarArg || p.HasGenerator() {
+			return fmt.Errorf("command %s: only flags can be hidden or deprecated, not %s", cmdName, p.Name)
+		}
+		if p.Replacement == "" {
+			continue
+		}
+		other := lookupParameter(params, p.Replacement)
+		switch {
+		case other == nil:
+			return fmt.Errorf("command %s: parameter %s is replaced by unknown flag %s", cmdName, p.Name, p.Replacement)
+		case other == p:
+			return fmt.Errorf("command %s: parameter %s cannot replace itself", cmdName, p.Name)
+		case other.IsPositional || other.IsVarArg || other.HasGenerator() || other.InheritedFrom != "":
+			return fmt.Errorf("command %s: parameter %s is replaced by %s, which is not a flag of this command", cmdName, p.Name, p.Replacement)
+		case other.Type != p.Type:
+			return fmt.Errorf("command %s: parameter %s of type %s cannot be replaced by %s of type %s", cmdName, p.Name, p.Type, other.Name, other.Type)
+		}
+		p.ReplacementParam = other
+		other.replaced = true
+	}
+	return nil
+}
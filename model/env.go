@@ -0,0 +1,62 @@
+package model
+
+import (
+	"strings"
+)
+
+// EnvVarName derives an environment variable name from a flag name, e.g. ("APP", "dry-run") -> "APP_DRY_RUN".
+func EnvVarName(prefix, flagName string) string {
+	var b strings.Builder
+	if prefix = strings.TrimRight(prefix, "_"); prefix != "" {
+		b.WriteString(strings.ToUpper(prefix))
+		b.WriteString("_")
+	}
+	for _, r := range strings.TrimLeft(flagName, "-") {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	return strings.ToUpper(b.String())
+}
+
+// ResolveEnv fills in the environment variable names of flags that either asked for a
+// derived name with a bare (env) attribute, or belong to a command with an EnvPrefix.
+// Subcommands inherit the prefix of their closest ancestor declaring one, followed by
+// their path below it, so that "app users create --name" under "EnvPrefix: APP" reads
+// APP_USERS_CREATE_NAME and flags of the same name in two subcommands stay apart.
+// Parameters inherited from a parent command are skipped here and pick up the parent's
+// name in ResolveInheritance, so ResolveEnv must run first.
+func (cmd *Command) ResolveEnv() {
+	resolveParameterEnv(cmd.Parameters, cmd.MainCmdName, cmd.EnvPrefix)
+	for _, sc := range cmd.SubCommands {
+		sc.resolveEnv(cmd.EnvPrefix)
+	}
+}
+
+// resolveEnv resolves the environment variable names of the subcommand and its children,
+// where prefix is the derived name prefix of the parent command.
+func (sc *SubCommand) resolveEnv(prefix string) {
+	switch {
+	case sc.EnvPrefix != "":
+		prefix = sc.EnvPrefix
+	case prefix != "":
+		prefix = EnvVarName(prefix, sc.SubCommandName)
+	}
+	resolveParameterEnv(sc.Parameters, sc.SubCommandName, prefix)
+	for _, child := range sc.SubCommands {
+		child.resolveEnv(prefix)
+	}
+}
+
+func resolveParameterEnv(params []*FunctionParameter, cmdName, prefix string) {
+	for _, p := range params {
+		if p.Env != "" || p.IsPositional || p.HasGenerator() || (p.DeclaredIn != "" && p.DeclaredIn != cmdName) {
+			continue
+		}
+		if p.EnvFromName || prefix != "" {
+			p.Env = EnvVarName(prefix, p.PrimaryFlagName())
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Example is a line of an Examples block: a command line showing how to run a command,
+// with an optional explanation.
+type Example struct {
+	// Command is the command line as written, starting with the program name.
+	Command string
+	// Words are the words of Command, split as a shell would.
+	Words []string
+	// Description explains what the example does. It may be empty.
+	Description string
+}
+
+// ManCommand is Command escaped for roff.
+func (e *Example) ManCommand() string {
+	return strings.ReplaceAll(strings.ReplaceAll(e.Command, `\`, `\e`), "-", `\-`)
+}
+
+// ExampleArgs returns the arguments an example passes to the root command, the words
+// after the program name.
+func (cmd *Command) ExampleArgs(e *Example) []string {
+	return e.Words[1:]
+}
+
+// ExampleArgs returns the arguments an example passes to the subcommand, the words after
+// the subcommand path. Flags of its ancestors given before the end of the path are
+// parsed by those commands and left out.
+func (sc *SubCommand) ExampleArgs(e *Example) []string {
+	end, _ := matchPath(e.Words, sc.commandPath())
+	return e.Words[end:]
+}
+
+// pathWord is a word of the command line leading to a command.
+type pathWord struct {
+	// names are the names the word may be.
+	names []string
+	// flags are the flags of the command the previous words lead to, which may be given
+	// before this word.
+	flags []*FunctionParameter
+}
+
+// commandPath lists the words of the command line leading to the subcommand.
+func (sc *SubCommand) commandPath() []pathWord {
+	var path []pathWord
+	for c := sc; c != nil; c = c.Parent {
+		word := pathWord{names: append([]string{c.SubCommandName}, c.Aliases...)}
+		if c.Parent != nil {
+			word.flags = c.Parent.ParsedParameters()
+		} else if c.Command != nil {
+			word.flags = c.Command.FlagParameters()
+		}
+		path = append([]pathWord{word}, path...)
+	}
+	return append([]pathWord{{names: []string{sc.MainCmdName}}}, path...)
+}
+
+// matchPath checks that words start with the command line path, allowing the flags of
+// each command before the name of its subcommand, and returns the number of words the
+// path takes. A flag is followed by its value unless it is a boolean flag or is written
+// with =. A flag the command does not declare is an error, as that command would reject
+// it before the rest of the path is read.
+func matchPath(words []string, path []pathWord) (int, error) {
+	var prog []string
+	for _, word := range path {
+		prog = append(prog, word.names[0])
+	}
+	i := 0
+	for n, word := range path {
+		for n > 0 && i < len(words) && len(words[i]) > 1 && strings.HasPrefix(words[i], "-") && words[i] != "--" {
+			name, _, hasValue := strings.Cut(strings.TrimLeft(words[i], "-"), "=")
+			p := lookupParameter(word.flags, name)
+			if p == nil {
+				return 0, fmt.Errorf("gives %s, which is not a flag of %q", words[i], strings.Join(prog[:n], " "))
+			}
+			i++
+			if !hasValue && !p.IsBool() {
+				i++
+			}
+		}
+		if i >= len(words) || !slices.Contains(word.names, words[i]) {
+			return 0, fmt.Errorf("does not start with %q", strings.Join(prog, " "))
+		}
+		i++
+	}
+	return i, nil
+}
+
+// CommandExamples are the examples of one command, as listed in agents.md.
+type CommandExamples struct {
+	Name     string
+	Examples []*Example
+}
+
+// AllExamples returns the examples of the root command and of every subcommand that is
+// not hidden, in usage order.
+func (cmd *Command) AllExamples() []CommandExamples {
+	var all []CommandExamples
+	if len(cmd.Examples) > 0 {
+		all = append(all, CommandExamples{Name: cmd.MainCmdName, Examples: cmd.Examples})
+	}
+	var walk func([]*SubCommand)
+	walk = func(scs []*SubCommand) {
+		for _, sc := range scs {
+			if sc.Hidden {
+				continue
+			}
+			if len(sc.Examples) > 0 {
+				all = append(all, CommandExamples{Name: sc.ProgName(), Examples: sc.Examples})
+			}
+			walk(sc.SubCommands)
+		}
+	}
+	walk(cmd.SubCommands)
+	return all
+}
+
+// validateExamples checks that every example runs the command declaring it: it must
+// start with the program name and the command's path, possibly with flags declared by
+// the commands along it, and must not go on to name one of the command's subcommands, as
+// the generated test of an example only parses the arguments of its own command.
+func validateExamples(examples []*Example, path []pathWord, subCommands []*SubCommand, cmdName string) error {
+	for _, e := range examples {
+		end, err := matchPath(e.Words, path)
+		if err != nil {
+			return fmt.Errorf("command %s: example %q %w", cmdName, e.Command, err)
+		}
+		if len(e.Words) == end {
+			continue
+		}
+		next := e.Words[end]
+		for _, sc := range subCommands {
+			if sc.SubCommandName == next || slices.Contains(sc.Aliases, next) {
+				return fmt.Errorf("command %s: example %q runs subcommand %s, list it under that command", cmdName, e.Command, sc.SubCommandName)
+			}
+		}
+	}
+	return nil
+}
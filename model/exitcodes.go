@@ -0,0 +1,66 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExitCode maps the errors matching an error of a command's package to the code the
+// generated main exits with.
+type ExitCode struct {
+	// Error names a sentinel error variable, matched with errors.Is, or an error type,
+	// matched with errors.As. A pointer type is written as *T.
+	Error string
+	// IsType reports whether Error names a type rather than a variable.
+	IsType bool
+	// Package is the package Error is referred to through from the generated code, empty
+	// for package main.
+	Package string
+	// ImportPath is the import path of the package declaring Error.
+	ImportPath string
+	Code       int
+}
+
+// ImportAlias is the name the generated code imports the package of the error under.
+func (e *ExitCode) ImportAlias() string {
+	return importAlias(e.Package, e.ImportPath)
+}
+
+// Register is the statement adding the exit code to the registry of the generated cmd
+// package.
+func (e *ExitCode) Register() string {
+	if e.IsType {
+		name := strings.TrimPrefix(e.Error, "*")
+		typ := strings.Repeat("*", len(e.Error)-len(name)) + qualify(e.Package, name)
+		return fmt.Sprintf("cmd.RegisterExitCodeAs(new(%s), %d)", typ, e.Code)
+	}
+	return fmt.Sprintf("cmd.RegisterExitCode(%s, %d)", qualify(e.Package, e.Error), e.Code)
+}
+
+// ExitCodePackages returns the exit codes whose errors are declared outside package main.
+func (cmd *Command) ExitCodePackages() []*ExitCode {
+	var codes []*ExitCode
+	for _, e := range cmd.ExitCodes {
+		if e.Package != "" {
+			codes = append(codes, e)
+		}
+	}
+	return codes
+}
+
+// validateExitCodes checks that the codes are ones a process can exit with and that no
+// error is given two of them.
+func validateExitCodes(cmd *Command) error {
+	seen := make(map[string]int)
+	for _, e := range cmd.ExitCodes {
+		if e.Code < 1 || e.Code > 125 {
+			return fmt.Errorf("command %s: exit code %d of %s must be between 1 and 125", cmd.MainCmdName, e.Code, e.Error)
+		}
+		key := e.ImportPath + "." + e.Error
+		if code, ok := seen[key]; ok && code != e.Code {
+			return fmt.Errorf("command %s: %s is given exit codes %d and %d", cmd.MainCmdName, e.Error, code, e.Code)
+		}
+		seen[key] = e.Code
+	}
+	return nil
+}
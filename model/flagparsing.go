@@ -0,0 +1,29 @@
+package model
+
+// Ways the generated code parses the flags of a command, set by a FlagParsing directive.
+const (
+	// FlagParsingStrict stops reading flags at the first positional argument. It is the default.
+	FlagParsingStrict = "strict"
+	// FlagParsingInterspersed reads flags anywhere before "--", so they may follow
+	// positional arguments. A subcommand name before any positional argument still
+	// hands the rest of the command line to the subcommand.
+	FlagParsingInterspersed = "interspersed"
+)
+
+// InterspersedFlags reports whether the root command accepts flags after its positional
+// arguments.
+func (cmd *Command) InterspersedFlags() bool {
+	return cmd.FlagParsing == FlagParsingInterspersed
+}
+
+// InterspersedFlags reports whether the subcommand accepts flags after its positional
+// arguments. A subcommand without a FlagParsing directive follows its closest ancestor
+// that has one, up to the root command.
+func (sc *SubCommand) InterspersedFlags() bool {
+	for c := sc; c != nil; c = c.Parent {
+		if c.FlagParsing != "" {
+			return c.FlagParsing == FlagParsingInterspersed
+		}
+	}
+	return sc.Command != nil && sc.Command.InterspersedFlags()
+}
@@ -0,0 +1,208 @@
+package model
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Hook is a function named by a Before or After directive. It runs around the action of
+// the command declaring it and of every command below it.
+type Hook struct {
+	// Func is the name of the hook function, declared in the package of the command.
+	Func string
+	// Package is the package Func is called through from the generated code, empty for
+	// package main.
+	Package string
+	// ImportPath is the import path of the package declaring Func.
+	ImportPath string
+	// Params are the parameters of Func, in order.
+	Params []HookParam
+	// ReturnsError reports whether Func returns an error.
+	ReturnsError bool
+}
+
+// HookParam is a parameter of a hook function. It is passed the field of the same name
+// of the declaring command or one of its ancestors, or the command's context.
+type HookParam struct {
+	Name      string
+	IsContext bool
+}
+
+// ImportAlias is the name the generated code imports the hook's package under, or empty
+// when the package name matches the last element of its import path.
+func (h *Hook) ImportAlias() string {
+	return importAlias(h.Package, h.ImportPath)
+}
+
+// importAlias is the name to import the package at importPath under for it to be
+// referred to as pkg, or empty when that is the last element of the import path.
+func importAlias(pkg, importPath string) string {
+	if pkg != "" && pkg != path.Base(importPath) {
+		return pkg
+	}
+	return ""
+}
+
+// HookCall is a hook as called from the generated code of one command.
+type HookCall struct {
+	*Hook
+	// Args are the Go expressions passed to the hook, in order.
+	Args []string
+}
+
+// Call is the call expression of the hook.
+func (h *HookCall) Call() string {
+	return qualify(h.Package, h.Func) + "(" + strings.Join(h.Args, ", ") + ")"
+}
+
+// HookLevel holds the hooks one command declares, as called by a command at or below it.
+type HookLevel struct {
+	Before *HookCall
+	After  *HookCall
+}
+
+// hookLevel is a command of the chain from the root to the command being run.
+type hookLevel struct {
+	name string
+	// owner is the field of the running command's struct through which this command's
+	// struct is reached, empty for the running command itself.
+	owner         string
+	params        []*FunctionParameter
+	before, after *Hook
+}
+
+// rootHookLevel is the root command as a level of a hook chain.
+func (cmd *Command) rootHookLevel(owner string) hookLevel {
+	return hookLevel{name: cmd.MainCmdName, owner: owner, params: cmd.SignatureParameters(), before: cmd.Before, after: cmd.After}
+}
+
+// hookChain lists the commands from the root down to sc.
+func (sc *SubCommand) hookChain() []hookLevel {
+	var chain []hookLevel
+	for c := sc; c != nil; c = c.Parent {
+		owner := c.SubCommandStructName
+		if c == sc {
+			owner = ""
+		}
+		chain = append([]hookLevel{{name: c.SubCommandName, owner: owner, params: c.SignatureParameters(), before: c.Before, after: c.After}}, chain...)
+	}
+	if sc.Command != nil {
+		chain = append([]hookLevel{sc.Command.rootHookLevel("RootCmd")}, chain...)
+	}
+	return chain
+}
+
+// resolveHooks turns the hooks of the chain into calls made from the last command of
+// it, root first.
+func resolveHooks(chain []hookLevel) ([]HookLevel, error) {
+	var levels []HookLevel
+	for i, level := range chain {
+		var l HookLevel
+		for _, h := range []struct {
+			hook *Hook
+			call **HookCall
+		}{{level.before, &l.Before}, {level.after, &l.After}} {
+			if h.hook == nil {
+				continue
+			}
+			call := &HookCall{Hook: h.hook}
+			for _, p := range h.hook.Params {
+				arg, err := hookArg(chain[:i+1], p)
+				if err != nil {
+					return nil, fmt.Errorf("command %s: %w", level.name, err)
+				}
+				call.Args = append(call.Args, arg)
+			}
+			*h.call = call
+		}
+		if l.Before != nil || l.After != nil {
+			levels = append(levels, l)
+		}
+	}
+	return levels, nil
+}
+
+// hookArg finds the field a hook parameter is passed, looking at the declaring command,
+// the last of chain, first and then at its ancestors.
+func hookArg(chain []hookLevel, p HookParam) (string, error) {
+	if p.IsContext {
+		return "c.hookContext", nil
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		for _, param := range chain[i].params {
+			if param.Name != p.Name || param.HasGenerator() {
+				continue
+			}
+			field := "c"
+			if chain[i].owner != "" {
+				field += "." + chain[i].owner
+			}
+			return field + "." + param.ValueFieldName(), nil
+		}
+	}
+	return "", fmt.Errorf("hook parameter %s is not a parameter of %s or of the commands above it", p.Name, chain[len(chain)-1].name)
+}
+
+// Hooks returns the Before and After hooks run around the root command's action, which
+// are its own.
+func (cmd *Command) Hooks() []HookLevel {
+	levels, _ := resolveHooks([]hookLevel{cmd.rootHookLevel("")})
+	return levels
+}
+
+// Hooks returns the Before and After hooks run around the subcommand's action: those of
+// the root, of each ancestor and of the subcommand itself, root first.
+func (sc *SubCommand) Hooks() []HookLevel {
+	levels, _ := resolveHooks(sc.hookChain())
+	return levels
+}
+
+// hookImports lists the hooks of levels that are declared outside package main.
+func hookImports(levels []HookLevel) []*Hook {
+	var hooks []*Hook
+	for _, l := range levels {
+		for _, c := range []*HookCall{l.Before, l.After} {
+			if c != nil && c.Package != "" {
+				hooks = append(hooks, c.Hook)
+			}
+		}
+	}
+	return hooks
+}
+
+// HookPackages returns the hooks the generated subcommand calls from another package.
+func (sc *SubCommand) HookPackages() []*Hook {
+	if !sc.HasAction() {
+		return nil
+	}
+	return hookImports(sc.Hooks())
+}
+
+// HookPackages returns the hooks the generated root command calls from another package.
+func (cmd *Command) HookPackages() []*Hook {
+	if cmd.FunctionName == "" {
+		return nil
+	}
+	return hookImports(cmd.Hooks())
+}
+
+// validateHooks checks that every hook parameter names a field the hook can be passed.
+func validateHooks(cmd *Command) error {
+	if _, err := resolveHooks([]hookLevel{cmd.rootHookLevel("")}); err != nil {
+		return err
+	}
+	var walk func([]*SubCommand) error
+	walk = func(scs []*SubCommand) error {
+		for _, sc := range scs {
+			if _, err := resolveHooks(sc.hookChain()); err != nil {
+				return err
+			}
+			if err := walk(sc.SubCommands); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return walk(cmd.SubCommands)
+}
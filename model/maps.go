@@ -0,0 +1,253 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// scalarTypes are the types a map flag accepts as key or value.
+var scalarTypes = []string{
+	"string", "bool", "time.Duration",
+	"int", "int64", "int32", "int16", "int8",
+	"uint", "uint64", "uint32", "uint16", "uint8",
+	"float64", "float32",
+}
+
+// IsMap reports whether the parameter is a map, set by repeating the flag with key=value.
+func (p *FunctionParameter) IsMap() bool {
+	return strings.HasPrefix(p.Type, "map[")
+}
+
+// MapKey describes the key type of a map parameter, so that its parser can be reused.
+func (p *FunctionParameter) MapKey() *FunctionParameter {
+	key, _ := splitMapType(p.Type)
+	return &FunctionParameter{Name: p.Name, Type: key}
+}
+
+// MapValue describes the value type of a map parameter.
+func (p *FunctionParameter) MapValue() *FunctionParameter {
+	_, value := splitMapType(p.Type)
+	return &FunctionParameter{Name: p.Name, Type: value}
+}
+
+// MapHelperName is the flag.Value type generated for the map's key and value types,
+// e.g. StringIntMap for map[string]int.
+func (p *FunctionParameter) MapHelperName() string {
+	return helperTypeName(p.MapKey().Type) + helperTypeName(p.MapValue().Type) + "Map"
+}
+
+// MapUsesStrconv reports whether parsing the key or value of a map parameter needs strconv.
+func (p *FunctionParameter) MapUsesStrconv() bool {
+	return strings.HasPrefix(p.MapKey().ParserCall(""), "strconv.") || strings.HasPrefix(p.MapValue().ParserCall(""), "strconv.")
+}
+
+// MapUsesDuration reports whether the key or value of a map parameter is a time.Duration.
+func (p *FunctionParameter) MapUsesDuration() bool {
+	return p.MapKey().IsDuration() || p.MapValue().IsDuration()
+}
+
+// MapSampleValue is a key=value entry the generated tests pass to a map flag.
+func (p *FunctionParameter) MapSampleValue() string {
+	return sampleScalar(p.MapKey().Type) + "=" + sampleScalar(p.MapValue().Type)
+}
+
+// MapDefaultLiteral renders the default of a map parameter, written as {key: value, ...},
+// as a Go composite literal.
+func (p *FunctionParameter) MapDefaultLiteral() string {
+	lit, err := mapDefaultLiteral(p)
+	if err != nil {
+		return "nil"
+	}
+	return lit
+}
+
+// MapHelperParameters returns one map parameter for each distinct map type used by the
+// command or its subcommands, for which a flag.Value helper is generated.
+func (cmd *Command) MapHelperParameters() []*FunctionParameter {
+	var params []*FunctionParameter
+	seen := map[string]bool{}
+	add := func(ps []*FunctionParameter) {
+		for _, p := range ps {
+			if p.IsMap() && !seen[p.MapHelperName()] {
+				seen[p.MapHelperName()] = true
+				params = append(params, p)
+			}
+		}
+	}
+	add(cmd.Parameters)
+	var walk func([]*SubCommand)
+	walk = func(scs []*SubCommand) {
+		for _, sc := range scs {
+			add(sc.Parameters)
+			walk(sc.SubCommands)
+		}
+	}
+	walk(cmd.SubCommands)
+	return params
+}
+
+func validateMap(p *FunctionParameter, cmdName string) error {
+	if !p.IsMap() {
+		return nil
+	}
+	key, value := splitMapType(p.Type)
+	if !slices.Contains(scalarTypes, key) || !slices.Contains(scalarTypes, value) {
+		return fmt.Errorf("command %s: map parameter %s must have scalar key and value types, not %s", cmdName, p.Name, p.Type)
+	}
+	if p.IsPositional {
+		return fmt.Errorf("command %s: map parameter %s cannot be a positional argument", cmdName, p.Name)
+	}
+	if len(p.Choices) > 0 {
+		return fmt.Errorf("command %s: map parameter %s cannot have choices", cmdName, p.Name)
+	}
+	if p.HasDefaultValue && p.Default != "" {
+		if _, err := mapDefaultLiteral(p); err != nil {
+			return fmt.Errorf("command %s: default of map parameter %s: %w", cmdName, p.Name, err)
+		}
+	}
+	return nil
+}
+
+// splitMapType returns the key and value types of "map[K]V".
+func splitMapType(t string) (string, string) {
+	t = strings.TrimPrefix(t, "map[")
+	depth := 1
+	for i, r := range t {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return t[:i], t[i+1:]
+			}
+		}
+	}
+	return "", ""
+}
+
+func mapDefaultLiteral(p *FunctionParameter) (string, error) {
+	def := strings.TrimSpace(p.Default)
+	if def == "nil" || def == "" {
+		return "nil", nil
+	}
+	if !strings.HasPrefix(def, "{") || !strings.HasSuffix(def, "}") {
+		return "", fmt.Errorf("expected {key: value, ...}, got %s", def)
+	}
+	key, value := splitMapType(p.Type)
+	var entries []string
+	for _, entry := range splitOutsideQuotes(def[1:len(def)-1], ',') {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		kv := splitOutsideQuotes(entry, ':')
+		if len(kv) < 2 {
+			return "", fmt.Errorf("entry %q is not key: value", strings.TrimSpace(entry))
+		}
+		k, err := scalarLiteral(key, kv[0])
+		if err != nil {
+			return "", err
+		}
+		v, err := scalarLiteral(value, strings.Join(kv[1:], ":"))
+		if err != nil {
+			return "", err
+		}
+		entries = append(entries, k+": "+v)
+	}
+	return p.Type + "{" + strings.Join(entries, ", ") + "}", nil
+}
+
+// splitOutsideQuotes splits s at sep, ignoring separators inside double quotes.
+func splitOutsideQuotes(s string, sep rune) []string {
+	var parts []string
+	inQuote, escaped := false, false
+	start := 0
+	for i, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\' && inQuote:
+			escaped = true
+		case r == '"':
+			inQuote = !inQuote
+		case r == sep && !inQuote:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// scalarLiteral checks that raw is a valid value of type t and returns it as Go source.
+func scalarLiteral(t, raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if strings.HasPrefix(raw, `"`) {
+		u, err := strconv.Unquote(raw)
+		if err != nil {
+			return "", fmt.Errorf("invalid quoted value %s", raw)
+		}
+		raw = u
+	}
+	var err error
+	switch {
+	case t == "string":
+		return strconv.Quote(raw), nil
+	case t == "bool":
+		var b bool
+		b, err = strconv.ParseBool(raw)
+		raw = strconv.FormatBool(b)
+	case t == "time.Duration":
+		var d time.Duration
+		d, err = time.ParseDuration(raw)
+		raw = strconv.FormatInt(int64(d), 10)
+	case strings.HasPrefix(t, "int"):
+		_, err = strconv.ParseInt(raw, 10, bitSize(t, "int"))
+	case strings.HasPrefix(t, "uint"):
+		_, err = strconv.ParseUint(raw, 10, bitSize(t, "uint"))
+	case strings.HasPrefix(t, "float"):
+		_, err = strconv.ParseFloat(raw, bitSize(t, "float"))
+	}
+	if err != nil {
+		return "", fmt.Errorf("invalid %s value %q", t, raw)
+	}
+	return raw, nil
+}
+
+func bitSize(t, prefix string) int {
+	n, err := strconv.Atoi(strings.TrimPrefix(t, prefix))
+	if err != nil {
+		return strconv.IntSize
+	}
+	return n
+}
+
+func sampleScalar(t string) string {
+	switch {
+	case t == "string":
+		return "key"
+	case t == "bool":
+		return "true"
+	case t == "time.Duration":
+		return "1s"
+	default:
+		return "1"
+	}
+}
+
+// helperTypeName is the name prefix the flag helper types use for t, e.g. Duration
+// for time.Duration.
+func helperTypeName(t string) string {
+	if t == "time.Duration" {
+		return "Duration"
+	}
+	r := []rune(t)
+	if len(r) == 0 {
+		return ""
+	}
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
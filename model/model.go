@@ -6,6 +6,7 @@ import (
 	"path"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -54,6 +55,13 @@ type SourceType string
 const (
 	SourceTypeFlag      SourceType = "flag"
 	SourceTypeGenerator SourceType = "generator"
+	// SourceTypeContext marks a context.Context parameter, which receives the context the command runs with.
+	SourceTypeContext SourceType = "context"
+	// SourceTypeStdin, SourceTypeStdout and SourceTypeStderr mark io.Reader and io.Writer
+	// parameters that receive one of the root command's IO streams.
+	SourceTypeStdin  SourceType = "stdin"
+	SourceTypeStdout SourceType = "stdout"
+	SourceTypeStderr SourceType = "stderr"
 )
 
 type ParserType string
@@ -108,10 +116,37 @@ type Command struct {
 	DocEnd token.Pos
 	// Parameters is the list of parameters (flags and arguments) for the command.
 	Parameters []*FunctionParameter
+	// Signature lists the function's parameters in declaration order when a struct
+	// parameter was expanded into Parameters; nil otherwise.
+	Signature []*FunctionParameter
+	// Receiver is set when the command is a method rather than a function.
+	Receiver *Receiver
 	// ReturnsError indicates if the command function returns an error.
 	ReturnsError bool
 	// ReturnCount is the number of return values.
 	ReturnCount int
+	// Before and After are the hooks run before and after the action of the command and
+	// of every subcommand.
+	Before *Hook
+	After  *Hook
+	// ExitCodes map the errors of the commands' packages to exit codes, collected from the
+	// ExitCodes directives of the command and its subcommands.
+	ExitCodes []*ExitCode
+	// HelpTopics are the topics help can show besides commands, sorted by name.
+	HelpTopics []*HelpTopic
+	// Examples are the command lines of the command's Examples block.
+	Examples []*Example
+	// outputParam is the --output flag, created by OutputParameter.
+	outputParam *FunctionParameter
+	// EnvPrefix, when set, binds every flag of the command and its subcommands to an environment variable.
+	EnvPrefix string
+	// ConfigFormats lists the config file formats accepted by the root --config flag. Empty disables it.
+	ConfigFormats []string
+	// FlagGroups are the OneOf and AtLeastOne constraints on the command's flags.
+	FlagGroups []*FlagGroup
+	// FlagParsing is FlagParsingStrict or FlagParsingInterspersed, and applies to the
+	// subcommands that do not set their own. Empty is strict.
+	FlagParsing string
 }
 
 // FunctionParameter represents a parameter of a command function, which can be a flag or a positional argument.
@@ -183,6 +218,74 @@ type FunctionParameter struct {
 	// InheritedFrom is the parent parameter name referenced by a differently named child parameter.
 	// It is parser metadata and is intentionally excluded from serialized models.
 	InheritedFrom string `json:"-"`
+	// Env is the environment variable read when the flag is not given on the command line.
+	Env string
+	// EnvFromName requests that Env is derived from the flag name once the command tree is known.
+	EnvFromName bool `json:"-"`
+	// ConfigKey is the dotted config file key the flag is read from when neither the flag nor Env is set.
+	ConfigKey string
+	// Choices, when set, is the list of values the parameter accepts.
+	Choices []string
+	// Layout is the time.Parse layout, or the name of a time package layout constant,
+	// of a time.Time parameter. It defaults to RFC 3339.
+	Layout string
+	// Encoding is how a []byte parameter is written on the command line: base64 (the
+	// default) or hex.
+	Encoding string
+	// Conversion is the method, UnmarshalText or Set, that parses a parameter whose type
+	// implements encoding.TextUnmarshaler or flag.Value. It is found from type information.
+	Conversion string
+	// TypePackage is the package declaring a type resolved from type information, which
+	// the generated code imports to refer to it.
+	TypePackage *FuncRef
+	// Min and Max bound a numeric or time.Duration parameter, written as Go literals.
+	Min string
+	Max string
+	// MinLen and MaxLen bound the length in characters of a string parameter. Zero is no bound.
+	MinLen int
+	MaxLen int
+	// Pattern is a regular expression a string parameter must match.
+	Pattern string
+	// NonEmpty rejects an empty string, or a slice or map without entries.
+	NonEmpty bool
+	// Conflicts names the flags of the same command that cannot be given with this one.
+	Conflicts []string
+	// Requires names the flags of the same command that must be given with this one.
+	Requires []string
+	// ConflictParams and RequiredParams are the parameters Conflicts and Requires refer
+	// to. They are set by validation.
+	ConflictParams []*FunctionParameter `json:"-"`
+	RequiredParams []*FunctionParameter `json:"-"`
+	// conflictedBy lists the parameters whose ConflictParams hold this one.
+	conflictedBy []*FunctionParameter
+	// constrained is set by validation when a conflict, requirement or flag group
+	// involves the flag.
+	constrained bool
+	// Persistent makes the subcommands below the declaring command accept the flag too,
+	// storing it on the declaring command.
+	Persistent bool
+	// PersistentOwner is set on a parameter taken from a parent when that parent's flag
+	// is persistent. It names the generated struct of the ancestor storing the value.
+	PersistentOwner string `json:"-"`
+	// Hidden leaves the flag out of usage output, man pages and completion.
+	Hidden bool
+	// Deprecated makes the generated code warn when the flag is given, with
+	// DeprecationNote when set.
+	Deprecated      bool
+	DeprecationNote string
+	// Replacement names the flag of the same command a deprecated flag passes its value
+	// on to. ReplacementParam is the parameter it refers to, set by validation.
+	Replacement      string
+	ReplacementParam *FunctionParameter `json:"-"`
+	// replaced is set by validation on the flag a deprecated flag is replaced by.
+	replaced bool
+	// Prompt asks for the value on the terminal when it is missing, with PromptLabel as
+	// the label when set. A prompted flag is required.
+	Prompt      bool
+	PromptLabel string
+	// Secret keeps the value off the screen when it is prompted for and out of usage
+	// output, and lets a flag be given as @path or - to read it from a file or stdin.
+	Secret bool
 }
 
 func (dm *DataModel) Validate() error {
@@ -198,6 +301,37 @@ func (cmd *Command) Validate() error {
 	if err := validateParameters(cmd.Parameters, cmd.MainCmdName); err != nil {
 		return err
 	}
+	if err := resolveFlagConstraints(cmd.Parameters, cmd.FlagGroups, cmd.MainCmdName); err != nil {
+		return err
+	}
+	if err := resolveDeprecations(cmd.Parameters, cmd.MainCmdName); err != nil {
+		return err
+	}
+	if err := validatePersistent(cmd.Parameters, cmd.MainCmdName); err != nil {
+		return err
+	}
+	if err := validateOutput(cmd); err != nil {
+		return err
+	}
+	if err := validateHooks(cmd); err != nil {
+		return err
+	}
+	if err := validateExitCodes(cmd); err != nil {
+		return err
+	}
+	if err := validateHelpTopics(cmd); err != nil {
+		return err
+	}
+	if err := validateExamples(cmd.Examples, []pathWord{{names: []string{cmd.MainCmdName}}}, cmd.SubCommands, cmd.MainCmdName); err != nil {
+		return err
+	}
+	if cmd.HasConfigFile() {
+		for _, p := range cmd.Parameters {
+			if !p.IsPositional && slices.Contains(p.FlagNames(), "--"+ConfigFlagName) {
+				return fmt.Errorf("command %s: flag --%s of parameter %s is reserved for the config file", cmd.MainCmdName, ConfigFlagName, p.Name)
+			}
+		}
+	}
 	for _, sc := range cmd.SubCommands {
 		if err := sc.Validate(); err != nil {
 			return err
@@ -210,6 +344,18 @@ func (sc *SubCommand) Validate() error {
 	if err := validateParameters(sc.Parameters, sc.SubCommandName); err != nil {
 		return err
 	}
+	if err := resolveFlagConstraints(sc.Parameters, sc.FlagGroups, sc.SubCommandName); err != nil {
+		return err
+	}
+	if err := resolveDeprecations(sc.Parameters, sc.SubCommandName); err != nil {
+		return err
+	}
+	if err := validatePersistent(sc.Parameters, sc.SubCommandName); err != nil {
+		return err
+	}
+	if err := validateExamples(sc.Examples, sc.commandPath(), sc.SubCommands, sc.SubCommandName); err != nil {
+		return err
+	}
 	for _, child := range sc.SubCommands {
 		if err := child.Validate(); err != nil {
 			return err
@@ -222,10 +368,35 @@ func validateParameters(params []*FunctionParameter, cmdName string) error {
 	var hasOptionalPos bool
 	var hasVarArg bool
 	for _, p := range params {
+		if err := validateChoices(p, cmdName); err != nil {
+			return err
+		}
+		if err := validateStream(p, cmdName); err != nil {
+			return err
+		}
+		if err := validateMap(p, cmdName); err != nil {
+			return err
+		}
+		if err := validateStdlibType(p, cmdName); err != nil {
+			return err
+		}
+		if err := validateValueChecks(p, cmdName); err != nil {
+			return err
+		}
+		if err := validatePrompt(p, cmdName); err != nil {
+			return err
+		}
+		if err := validateConversion(p, cmdName); err != nil {
+			return err
+		}
 		if !p.IsPositional {
 			continue
 		}
 
+		if p.Env != "" {
+			return fmt.Errorf("command %s: positional argument %s cannot be bound to environment variable %s", cmdName, p.Name, p.Env)
+		}
+
 		if p.IsVarArg {
 			hasVarArg = true
 			continue
@@ -246,6 +417,38 @@ func validateParameters(params []*FunctionParameter, cmdName string) error {
 	return nil
 }
 
+func validateStream(p *FunctionParameter, cmdName string) error {
+	want := "io.Writer"
+	switch p.Generator.Type {
+	case SourceTypeStdin:
+		want = "io.Reader"
+	case SourceTypeStdout, SourceTypeStderr:
+	default:
+		return nil
+	}
+	if p.Type != want || p.IsVarArg {
+		return fmt.Errorf("command %s: parameter %s marked (%s) must be of type %s, not %s", cmdName, p.Name, p.Generator.Type, want, p.Type)
+	}
+	return nil
+}
+
+func validateChoices(p *FunctionParameter, cmdName string) error {
+	if len(p.Choices) == 0 {
+		return nil
+	}
+	if p.IsBool() {
+		return fmt.Errorf("command %s: boolean parameter %s cannot have choices", cmdName, p.Name)
+	}
+	def := p.Default
+	if u, err := strconv.Unquote(def); err == nil {
+		def = u
+	}
+	if p.HasDefaultValue && def != "" && !slices.Contains(p.Choices, def) {
+		return fmt.Errorf("command %s: default %q of parameter %s is not one of its choices: %s", cmdName, def, p.Name, p.ChoicesString())
+	}
+	return nil
+}
+
 func (p *FunctionParameter) FlagString() string {
 	var parts []string
 	if len(p.FlagAliases) > 0 {
@@ -285,6 +488,9 @@ func (p *FunctionParameter) PrimaryFlagName() string {
 
 // ValueFieldName returns the generated command field that supplies this argument.
 func (p *FunctionParameter) ValueFieldName() string {
+	if p.PersistentOwner != "" {
+		return p.PersistentOwner + "." + p.InheritedFrom
+	}
 	if p.InheritedFrom != "" {
 		return p.InheritedFrom
 	}
@@ -300,6 +506,9 @@ func (p *FunctionParameter) DefaultString() string {
 	if p.Default == "" && !p.HasDefaultValue {
 		return ""
 	}
+	if p.Secret && p.Default != "" {
+		return fmt.Sprintf("(default: %s)", secretMask)
+	}
 	def := p.Default
 	if p.Type == "string" && !strings.HasPrefix(def, "\"") {
 		def = fmt.Sprintf("%q", def)
@@ -315,13 +524,22 @@ func (p *FunctionParameter) DefaultString() string {
 	return fmt.Sprintf("(default: %s)", def)
 }
 
-// IsSlice returns true if the type is a slice.
+// IsSlice returns true if the type is a slice. A []byte parsed from base64 or hex is a
+// single value rather than a repeatable flag.
 func (p *FunctionParameter) IsSlice() bool {
+	if p.Type == "[]byte" && p.IsStdlibType() {
+		return false
+	}
 	return strings.HasPrefix(p.Type, "[]")
 }
 
-// HasPointer returns true if the type is a pointer (or slice of pointers).
+// HasPointer returns true if the type is a pointer (or slice of pointers). Standard
+// library types such as *url.URL, and types with a Conversion, are parsed to a pointer
+// already, so they do not count.
 func (p *FunctionParameter) HasPointer() bool {
+	if p.HasTypedParser() {
+		return false
+	}
 	t := p.Type
 	t = strings.TrimPrefix(t, "[]")
 	return strings.HasPrefix(t, "*")
@@ -358,6 +576,12 @@ func (p *FunctionParameter) ParserCall(valName string) string {
 		}
 		return fmt.Sprintf("%s(%s)", p.Parser.Func.FunctionName, valName)
 	}
+	if st := p.stdlib(); st != nil {
+		return st.parse(p, valName)
+	}
+	if p.hasConversion() {
+		return p.conversionCall("c."+p.Name, valName)
+	}
 
 	t := p.BaseType()
 	if t == "int" {
@@ -369,28 +593,25 @@ func (p *FunctionParameter) ParserCall(valName string) string {
 	if t == "bool" {
 		return fmt.Sprintf("strconv.ParseBool(%s)", valName)
 	}
-	if strings.HasPrefix(t, "int") {
-		bits := t[3:]
-		if bits == "" {
-			bits = "0"
-		}
-		return fmt.Sprintf("strconv.ParseInt(%s, 10, %s)", valName, bits)
-	}
-	if strings.HasPrefix(t, "uint") {
+	switch t {
+	case "int8", "int16", "int32", "int64":
+		return fmt.Sprintf("strconv.ParseInt(%s, 10, %s)", valName, t[3:])
+	case "uint", "uint8", "uint16", "uint32", "uint64":
 		bits := t[4:]
 		if bits == "" {
 			bits = "64"
 		}
 		return fmt.Sprintf("strconv.ParseUint(%s, 10, %s)", valName, bits)
-	}
-	if strings.HasPrefix(t, "float") {
-		bits := t[5:]
-		return fmt.Sprintf("strconv.ParseFloat(%s, %s)", valName, bits)
+	case "float32", "float64":
+		return fmt.Sprintf("strconv.ParseFloat(%s, %s)", valName, t[5:])
 	}
 	return ""
 }
 
 func (p *FunctionParameter) CastCode(valName string) string {
+	if p.HasTypedParser() {
+		return valName
+	}
 	t := p.BaseType()
 	// No cast needed if types match the parser return type
 	switch t {
@@ -403,6 +624,15 @@ func (p *FunctionParameter) CastCode(valName string) string {
 }
 
 func (p *FunctionParameter) TypeDescription() string {
+	if st := p.stdlib(); st != nil {
+		if st.description == "" {
+			return p.EncodingName()
+		}
+		return st.description
+	}
+	if p.hasConversion() {
+		return p.conversionDescription()
+	}
 	t := p.BaseType()
 	switch t {
 	case "int":
@@ -451,10 +681,33 @@ type SubCommand struct {
 	DocEnd token.Pos
 	// Parameters is the list of parameters for this subcommand.
 	Parameters []*FunctionParameter
+	// Signature lists the function's parameters in declaration order when a struct
+	// parameter was expanded into Parameters; nil otherwise.
+	Signature []*FunctionParameter
+	// Receiver is set when the subcommand is a method rather than a function.
+	Receiver *Receiver
 	// ReturnsError indicates if the function returns an error.
 	ReturnsError bool
 	// ReturnCount is the number of return values.
 	ReturnCount int
+	// EnvPrefix overrides the inherited environment variable prefix for this subcommand and its children.
+	EnvPrefix string
+	// FlagGroups are the OneOf and AtLeastOne constraints on the subcommand's flags.
+	FlagGroups []*FlagGroup
+	// FlagParsing overrides the inherited FlagParsing for this subcommand and its children.
+	FlagParsing string
+	// Before and After are the hooks run before and after the action of the subcommand
+	// and of every command below it.
+	Before *Hook
+	After  *Hook
+	// Hidden leaves the subcommand out of usage output, man pages and completion.
+	Hidden bool
+	// Deprecated makes the generated code warn when the subcommand runs, with
+	// DeprecationNote when set.
+	Deprecated      bool
+	DeprecationNote string
+	// Examples are the command lines of the subcommand's Examples block.
+	Examples []*Example
 }
 
 func (sc *SubCommand) ImportAlias() string {
@@ -511,7 +764,7 @@ func (sc *SubCommand) ProgName() string {
 func (sc *SubCommand) MaxFlagLength() int {
 	max := 0
 	for _, p := range sc.AllParameters() {
-		if p.HasGenerator() {
+		if p.HasGenerator() || p.Hidden {
 			continue
 		}
 		l := len(p.FlagString())
@@ -539,6 +792,30 @@ func (sc *SubCommand) HasRequiredFlags() bool {
 	return requiredFlagPresent(sc.Parameters)
 }
 
+// NeedsSeen reports whether the generated parser must record when this flag is given explicitly.
+func (p *FunctionParameter) NeedsSeen() bool {
+	return p.Required || p.Env != "" || p.ConfigKey != "" || p.constrained || p.HasValueChecks() || p.Deprecated || p.replaced
+}
+
+func seenFlagsNeeded(params []*FunctionParameter) bool {
+	for _, p := range params {
+		if p.NeedsSeen() && !p.IsPositional && !p.HasGenerator() {
+			return true
+		}
+	}
+	return false
+}
+
+// NeedsSeenFlags reports whether the generated Execute needs a seenFlags map.
+func (cmd *Command) NeedsSeenFlags() bool {
+	return seenFlagsNeeded(cmd.Parameters)
+}
+
+// NeedsSeenFlags reports whether the generated Execute needs a seenFlags map.
+func (sc *SubCommand) NeedsSeenFlags() bool {
+	return seenFlagsNeeded(sc.Parameters)
+}
+
 func (sc *SubCommand) ResolveInheritance() {
 	for _, p := range sc.Parameters {
 		if p.DeclaredIn != sc.SubCommandName && p.DeclaredIn != "" {
@@ -569,6 +846,12 @@ func (sc *SubCommand) ResolveInheritance() {
 					if p.Parser.Type == "" && parentParam.Parser.Type != "" {
 						p.Parser = parentParam.Parser
 					}
+					if p.Env == "" {
+						p.Env = parentParam.Env
+					}
+					if len(p.Choices) == 0 {
+						p.Choices = parentParam.Choices
+					}
 				}
 			} else if sc.Command != nil && sc.MainCmdName == p.DeclaredIn {
 				// Declared in Root Command
@@ -583,12 +866,19 @@ func (sc *SubCommand) ResolveInheritance() {
 						if len(p.FlagAliases) == 0 {
 							p.FlagAliases = pp.FlagAliases
 						}
+						if p.Env == "" {
+							p.Env = pp.Env
+						}
+						if len(p.Choices) == 0 {
+							p.Choices = pp.Choices
+						}
 						break
 					}
 				}
 			}
 		}
 	}
+	sc.linkPersistent()
 	for _, child := range sc.SubCommands {
 		child.ResolveInheritance()
 	}
@@ -635,7 +925,7 @@ func (sc *SubCommand) AllParameters() []*FunctionParameter {
 	}
 
 	if sc.Command != nil {
-		addParams(sc.Command.Parameters)
+		addParams(sc.Command.FlagParameters())
 	}
 	return params
 }
@@ -647,13 +937,16 @@ type ParameterGroup struct {
 	CommandName string
 	// Parameters is the list of parameters in this group.
 	Parameters []*FunctionParameter
+	// Inherited marks the group of persistent flags declared by ancestors.
+	Inherited bool
 }
 
 func (sc *SubCommand) ParameterGroups() []ParameterGroup {
 	allParams := sc.AllParameters()
+	persistent := sc.persistentParameters()
 	grouped := make(map[string][]*FunctionParameter)
 	for _, p := range allParams {
-		if p.IsPositional || p.HasGenerator() {
+		if p.IsPositional || p.HasGenerator() || p.Hidden || p.PersistentOwner != "" || slices.Contains(persistent, p) {
 			continue
 		}
 		grouped[p.DeclaredIn] = append(grouped[p.DeclaredIn], p)
@@ -712,13 +1005,23 @@ func (sc *SubCommand) ParameterGroups() []ParameterGroup {
 		})
 	}
 
+	var inherited []*FunctionParameter
+	for _, p := range persistent {
+		if !p.Hidden {
+			inherited = append(inherited, p)
+		}
+	}
+	if len(inherited) > 0 {
+		groups = append(groups, ParameterGroup{Parameters: inherited, Inherited: true})
+	}
+
 	return groups
 }
 
 func appendFlagsUsage(parts *[]string, params []*FunctionParameter) {
 	hasFlags := false
 	for _, p := range params {
-		if !p.IsPositional && !p.HasGenerator() {
+		if !p.IsPositional && !p.HasGenerator() && !p.Hidden {
 			hasFlags = true
 			break
 		}
@@ -734,7 +1037,7 @@ func (sc *SubCommand) FullUsageString() string {
 	// Add root command
 	if sc.Command != nil {
 		parts = append(parts, sc.MainCmdName)
-		appendFlagsUsage(&parts, sc.Command.Parameters)
+		appendFlagsUsage(&parts, sc.Command.FlagParameters())
 	}
 
 	// Traverse from root to current
@@ -759,11 +1062,11 @@ func (sc *SubCommand) FullUsageString() string {
 	for _, p := range sc.Parameters {
 		if p.IsPositional {
 			if p.IsVarArg {
-				parts = append(parts, fmt.Sprintf("[%s...]", p.Name))
+				parts = append(parts, fmt.Sprintf("[%s...]", p.ArgName()))
 			} else if p.HasDefaultValue {
-				parts = append(parts, fmt.Sprintf("[%s]", p.Name))
+				parts = append(parts, fmt.Sprintf("[%s]", p.ArgName()))
 			} else {
-				parts = append(parts, fmt.Sprintf("<%s>", p.Name))
+				parts = append(parts, fmt.Sprintf("<%s>", p.ArgName()))
 			}
 		}
 	}
@@ -778,7 +1081,7 @@ func (sc *SubCommand) FullUsageString() string {
 func (sc *SubCommand) MaxDefaultLength() int {
 	max := 0
 	for _, p := range sc.AllParameters() {
-		if p.IsPositional || p.HasGenerator() {
+		if p.IsPositional || p.HasGenerator() || p.Hidden {
 			continue
 		}
 		l := len(p.DefaultString())
@@ -789,8 +1092,102 @@ func (sc *SubCommand) MaxDefaultLength() int {
 	return max
 }
 
+// UsageDescription returns the description followed by annotations such as the bound environment variable.
+func (p *FunctionParameter) UsageDescription() string {
+	var parts []string
+	if p.Description != "" {
+		parts = append(parts, p.Description)
+	}
+	if len(p.Choices) > 0 {
+		parts = append(parts, fmt.Sprintf("(one of: %s)", p.ChoicesString()))
+	}
+	if p.IsStdlibType() {
+		switch p.elemType() {
+		case "time.Time":
+			parts = append(parts, fmt.Sprintf("(layout: %s)", p.LayoutValue()))
+		case "[]byte":
+			parts = append(parts, fmt.Sprintf("(%s)", p.EncodingName()))
+		}
+	}
+	if checks := p.ValueChecksString(); checks != "" {
+		parts = append(parts, checks)
+	}
+	if p.ReadsSecret() {
+		parts = append(parts, "(@file or - for stdin)")
+	}
+	if p.Env != "" {
+		parts = append(parts, fmt.Sprintf("(env: %s)", p.Env))
+	}
+	if conflicts := p.ConflictsString(); conflicts != "" {
+		parts = append(parts, fmt.Sprintf("(conflicts with: %s)", conflicts))
+	}
+	if len(p.RequiredParams) > 0 {
+		parts = append(parts, fmt.Sprintf("(requires: %s)", p.RequiresString()))
+	}
+	if p.Deprecated {
+		if note := p.deprecationNote(); note != "" {
+			parts = append(parts, fmt.Sprintf("(deprecated: %s)", note))
+		} else {
+			parts = append(parts, "(deprecated)")
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// ChoicesString returns the accepted values separated by commas.
+func (p *FunctionParameter) ChoicesString() string {
+	return strings.Join(p.Choices, ", ")
+}
+
+// SampleValue returns the value generated tests pass for the parameter: its first
+// choice when it has any, otherwise fallback adjusted to pass its value checks.
+func (p *FunctionParameter) SampleValue(fallback string) string {
+	if len(p.Choices) > 0 {
+		return p.Choices[0]
+	}
+	return p.checkedSample(fallback)
+}
+
+// EnvParameters returns the parameters, including inherited ones, that are bound to
+// environment variables. Hidden flags are left out.
+func (sc *SubCommand) EnvParameters() []*FunctionParameter {
+	var params []*FunctionParameter
+	for _, p := range sc.AllParameters() {
+		if p.Env != "" && !p.Hidden {
+			params = append(params, p)
+		}
+	}
+	return params
+}
+
+// HasGenerator reports whether the value is supplied by code rather than the command line.
+// Context parameters count as generated, their value being the command's context.
 func (p *FunctionParameter) HasGenerator() bool {
-	return p.Generator.Type == SourceTypeGenerator
+	return p.Generator.Type == SourceTypeGenerator || p.IsContext() || p.IsStream()
+}
+
+// IsStream reports whether the parameter receives one of the command's IO streams.
+func (p *FunctionParameter) IsStream() bool {
+	return p.StreamMethod() != ""
+}
+
+// StreamMethod returns the method of the generated IO struct that supplies the
+// parameter: Stdin, Stdout or Stderr. It is empty for other parameters.
+func (p *FunctionParameter) StreamMethod() string {
+	switch p.Generator.Type {
+	case SourceTypeStdin:
+		return "Stdin"
+	case SourceTypeStdout:
+		return "Stdout"
+	case SourceTypeStderr:
+		return "Stderr"
+	}
+	return ""
+}
+
+// IsContext reports whether the parameter receives the command's context.Context.
+func (p *FunctionParameter) IsContext() bool {
+	return p.Generator.Type == SourceTypeContext
 }
 
 func (p *FunctionParameter) GeneratorCall() string {
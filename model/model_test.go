@@ -2,6 +2,7 @@ package model
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -187,3 +188,837 @@ func TestCommandAndSubCommandHelpers(t *testing.T) {
 		t.Error("expected flag and default widths for inherited parameters")
 	}
 }
+
+func TestCommandCompletionNodes(t *testing.T) {
+	root := &Command{MainCmdName: "my-app"}
+	parent := &SubCommand{Command: root, SubCommandName: "Parent", Aliases: []string{"P"}}
+	child := &SubCommand{
+		Command:        root,
+		Parent:         parent,
+		SubCommandName: "child",
+		Parameters: []*FunctionParameter{
+			{Name: "name", Type: "string", FlagAliases: []string{"name", "n"}},
+			{Name: "verbose", Type: "bool"},
+			{Name: "target", Type: "string", IsPositional: true},
+			{Name: "dir", Type: "string", InheritedFrom: "parent"},
+		},
+	}
+	parent.SubCommands = []*SubCommand{child}
+	root.SubCommands = []*SubCommand{parent}
+
+	if got := root.CompletionFunctionName(); got != "_my_app" {
+		t.Errorf("CompletionFunctionName() = %q, want _my_app", got)
+	}
+
+	nodes := root.CompletionNodes()
+	gotPaths := make([]string, len(nodes))
+	for i, n := range nodes {
+		gotPaths[i] = n.Path
+	}
+	if want := []string{"", "Parent", "Parent child"}; !reflect.DeepEqual(gotPaths, want) {
+		t.Fatalf("CompletionNodes() paths = %v, want %v", gotPaths, want)
+	}
+	if got, want := nodes[0].CommandWords(), "parent p help usage version completion"; got != want {
+		t.Errorf("root CommandWords() = %q, want %q", got, want)
+	}
+	if got, want := nodes[1].CommandWords(), "child help usage"; got != want {
+		t.Errorf("parent CommandWords() = %q, want %q", got, want)
+	}
+	if got, want := nodes[2].FlagWords(), "--name -n --verbose --help -h"; got != want {
+		t.Errorf("child FlagWords() = %q, want %q", got, want)
+	}
+	flags := nodes[2].Flags
+	if !flags[0].TakesValue || flags[1].TakesValue {
+		t.Error("TakesValue should be set for value flags only")
+	}
+	if got := flags[0].LongNames(); !reflect.DeepEqual(got, []string{"name"}) {
+		t.Errorf("LongNames() = %v", got)
+	}
+	if got := flags[0].ShortNames(); !reflect.DeepEqual(got, []string{"n"}) {
+		t.Errorf("ShortNames() = %v", got)
+	}
+}
+
+func TestCommandResolveEnv(t *testing.T) {
+	if got := EnvVarName("APP_", "dry-run"); got != "APP_DRY_RUN" {
+		t.Errorf("EnvVarName() = %q, want APP_DRY_RUN", got)
+	}
+	if got := EnvVarName("", "log.level"); got != "LOG_LEVEL" {
+		t.Errorf("EnvVarName() = %q, want LOG_LEVEL", got)
+	}
+
+	root := &Command{
+		MainCmdName: "app",
+		Parameters: []*FunctionParameter{
+			{Name: "port", Type: "int", EnvFromName: true},
+			{Name: "verbose", Type: "bool"},
+		},
+	}
+	child := &SubCommand{
+		Command:        root,
+		SubCommandName: "serve",
+		EnvPrefix:      "APP",
+		Parameters: []*FunctionParameter{
+			{Name: "host", Type: "string", Env: "SERVE_HOST", Description: "Host"},
+			{Name: "dryRun", Type: "bool", FlagAliases: []string{"dry-run"}},
+			{Name: "target", Type: "string", IsPositional: true},
+		},
+	}
+	grandchild := &SubCommand{
+		Command:        root,
+		Parent:         child,
+		SubCommandName: "dry-run",
+		Parameters:     []*FunctionParameter{{Name: "dryRun", Type: "bool", FlagAliases: []string{"dry-run"}}},
+	}
+	child.SubCommands = []*SubCommand{grandchild}
+	root.SubCommands = []*SubCommand{child}
+	root.ResolveEnv()
+
+	if got := root.Parameters[0].Env; got != "PORT" {
+		t.Errorf("root (env) = %q, want PORT", got)
+	}
+	if got := root.Parameters[1].Env; got != "" {
+		t.Errorf("root flag without prefix = %q, want none", got)
+	}
+	if got := child.Parameters[0].Env; got != "SERVE_HOST" {
+		t.Errorf("explicit env = %q, want SERVE_HOST", got)
+	}
+	if got := child.Parameters[1].Env; got != "APP_DRY_RUN" {
+		t.Errorf("prefixed env = %q, want APP_DRY_RUN", got)
+	}
+	if got := grandchild.Parameters[0].Env; got != "APP_DRY_RUN_DRY_RUN" {
+		t.Errorf("env below the prefix = %q, want the subcommand path APP_DRY_RUN_DRY_RUN", got)
+	}
+	if got := child.Parameters[2].Env; got != "" {
+		t.Errorf("positional env = %q, want none", got)
+	}
+	if got := child.Parameters[0].UsageDescription(); got != "Host (env: SERVE_HOST)" {
+		t.Errorf("UsageDescription() = %q", got)
+	}
+	if got := len(child.EnvParameters()); got != 3 {
+		t.Errorf("EnvParameters() = %d, want 3 including the inherited root flag", got)
+	}
+	if !child.NeedsSeenFlags() || root.Parameters[1].NeedsSeen() {
+		t.Error("seenFlags should only be tracked for env bound or required flags")
+	}
+}
+
+func TestCommandResolveConfig(t *testing.T) {
+	root := &Command{
+		MainCmdName:   "app",
+		ConfigFormats: []string{ConfigFormatJSON},
+		Parameters:    []*FunctionParameter{{Name: "port", Type: "int", DeclaredIn: "app"}},
+	}
+	users := &SubCommand{Command: root, SubCommandName: "Users"}
+	create := &SubCommand{
+		Command:        root,
+		Parent:         users,
+		SubCommandName: "create",
+		Parameters: []*FunctionParameter{
+			{Name: "userName", Type: "string", FlagAliases: []string{"user-name", "u"}, DeclaredIn: "create"},
+			{Name: "target", Type: "string", IsPositional: true, DeclaredIn: "create"},
+		},
+	}
+	users.SubCommands = []*SubCommand{create}
+	root.SubCommands = []*SubCommand{users}
+	root.ResolveConfig()
+
+	if got := root.Parameters[0].ConfigKey; got != "port" {
+		t.Errorf("root ConfigKey = %q, want port", got)
+	}
+	if got := create.Parameters[0].ConfigKey; got != "users.create.user-name" {
+		t.Errorf("ConfigKey = %q, want users.create.user-name", got)
+	}
+	if got := create.Parameters[1].ConfigKey; got != "" {
+		t.Errorf("positional ConfigKey = %q, want none", got)
+	}
+	if got := len(root.FlagParameters()); got != 2 || len(root.Parameters) != 1 {
+		t.Errorf("FlagParameters() = %d, want the config flag appended without touching Parameters", got)
+	}
+	if got := root.ConfigParameter().Description; got != "Load flag values from a JSON file" {
+		t.Errorf("ConfigParameter().Description = %q", got)
+	}
+}
+
+func TestValidateStreamParameters(t *testing.T) {
+	tests := []struct {
+		name    string
+		param   *FunctionParameter
+		wantErr bool
+	}{
+		{"stdin reader", &FunctionParameter{Name: "in", Type: "io.Reader", Generator: GeneratorConfig{Type: SourceTypeStdin}}, false},
+		{"stderr writer", &FunctionParameter{Name: "log", Type: "io.Writer", Generator: GeneratorConfig{Type: SourceTypeStderr}}, false},
+		{"stdout reader", &FunctionParameter{Name: "out", Type: "io.Reader", Generator: GeneratorConfig{Type: SourceTypeStdout}}, true},
+		{"stdin string", &FunctionParameter{Name: "in", Type: "string", Generator: GeneratorConfig{Type: SourceTypeStdin}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateParameters([]*FunctionParameter{tt.param}, "app")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateParameters() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMapParameters(t *testing.T) {
+	p := &FunctionParameter{Name: "limits", Type: "map[string]time.Duration", Default: `{build: 1m, "push, tag": 30s}`, HasDefaultValue: true}
+	if got := p.MapHelperName(); got != "StringDurationMap" {
+		t.Errorf("MapHelperName() = %q, want StringDurationMap", got)
+	}
+	if got, want := p.MapDefaultLiteral(), `map[string]time.Duration{"build": 60000000000, "push, tag": 30000000000}`; got != want {
+		t.Errorf("MapDefaultLiteral() = %q, want %q", got, want)
+	}
+	if got := p.MapSampleValue(); got != "key=1s" {
+		t.Errorf("MapSampleValue() = %q, want key=1s", got)
+	}
+
+	tests := []struct {
+		name    string
+		param   *FunctionParameter
+		wantErr bool
+	}{
+		{"string map", &FunctionParameter{Name: "labels", Type: "map[string]string"}, false},
+		{"int keys", &FunctionParameter{Name: "ports", Type: "map[int]bool", Default: "{80: true}", HasDefaultValue: true}, false},
+		{"slice values", &FunctionParameter{Name: "groups", Type: "map[string][]string"}, true},
+		{"bad default", &FunctionParameter{Name: "limits", Type: "map[string]int", Default: "{cpu: lots}", HasDefaultValue: true}, true},
+		{"positional", &FunctionParameter{Name: "labels", Type: "map[string]string", IsPositional: true}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateParameters([]*FunctionParameter{tt.param}, "app")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateParameters() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStdlibParameters(t *testing.T) {
+	for _, tt := range []struct {
+		param      *FunctionParameter
+		parserCall string
+		sample     string
+		imports    []string
+	}{
+		{&FunctionParameter{Type: "time.Time"}, "time.Parse(time.RFC3339, s)", "2024-01-02T15:04:05Z", []string{"time"}},
+		{&FunctionParameter{Type: "time.Time", Layout: "DateOnly"}, "time.Parse(time.DateOnly, s)", "2024-01-02", []string{"time"}},
+		{&FunctionParameter{Type: "time.Time", Layout: "02/01/2006"}, `time.Parse("02/01/2006", s)`, "02/01/2024", []string{"time"}},
+		{&FunctionParameter{Type: "*url.URL"}, "url.Parse(s)", "https://example.com", []string{"net/url"}},
+		{&FunctionParameter{Type: "[]netip.Addr"}, "netip.ParseAddr(s)", "127.0.0.1", []string{"net/netip"}},
+		{&FunctionParameter{Type: "[]byte", Encoding: EncodingHex}, "hex.DecodeString(s)", "74657374", []string{"encoding/hex"}},
+		{&FunctionParameter{Type: "*big.Int"}, "parseBigInt(s)", "12345678901234567890", []string{"math/big"}},
+	} {
+		t.Run(tt.param.Type, func(t *testing.T) {
+			if got := tt.param.ParserCall("s"); got != tt.parserCall {
+				t.Errorf("ParserCall() = %q, want %q", got, tt.parserCall)
+			}
+			if got := tt.param.StdlibSampleValue(); got != tt.sample {
+				t.Errorf("StdlibSampleValue() = %q, want %q", got, tt.sample)
+			}
+			if got := tt.param.TypeImports(); !reflect.DeepEqual(got, tt.imports) {
+				t.Errorf("TypeImports() = %q, want %q", got, tt.imports)
+			}
+			if tt.param.HasPointer() {
+				t.Error("HasPointer() = true, want the parsed value assigned as is")
+			}
+		})
+	}
+
+	if p := (&FunctionParameter{Type: "[]byte"}); p.IsSlice() {
+		t.Error("[]byte should be a single value, not a repeatable flag")
+	}
+	if p := (&FunctionParameter{Type: "*url.URL", Parser: ParserConfig{Type: ParserTypeCustom, Func: &FuncRef{FunctionName: "ParseURL"}}}); p.IsStdlibType() {
+		t.Error("a custom parser should take precedence over the built-in one")
+	}
+
+	tests := []struct {
+		name    string
+		param   *FunctionParameter
+		wantErr bool
+	}{
+		{"time default", &FunctionParameter{Name: "since", Type: "time.Time", Layout: "2006-01-02", Default: "2024-02-30", HasDefaultValue: true}, true},
+		{"time expression default", &FunctionParameter{Name: "since", Type: "time.Time", Default: "time.Now()", HasDefaultValue: true}, false},
+		{"url default", &FunctionParameter{Name: "endpoint", Type: "*url.URL", Default: "example.com", HasDefaultValue: true}, false},
+		{"ip default", &FunctionParameter{Name: "bind", Type: "net.IP", Default: "localhost", HasDefaultValue: true}, true},
+		{"layout on string", &FunctionParameter{Name: "name", Type: "string", Layout: "2006"}, true},
+		{"unknown encoding", &FunctionParameter{Name: "key", Type: "[]byte", Encoding: "base32"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateParameters([]*FunctionParameter{tt.param}, "app")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateParameters() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConversionParameters(t *testing.T) {
+	for _, tt := range []struct {
+		param *FunctionParameter
+		want  string
+	}{
+		{&FunctionParameter{Name: "level", Type: "app.Level", Conversion: ConversionUnmarshalText},
+			"func(s string) (app.Level, error) { var v app.Level; err := v.UnmarshalText([]byte(s)); return v, err }(value)"},
+		{&FunctionParameter{Name: "color", Type: "*colors.Color", Conversion: ConversionSet},
+			"func(v *colors.Color, s string) (*colors.Color, error) { if v == nil { v = new(colors.Color) }; err := v.Set(s); return v, err }(c.color, value)"},
+		{&FunctionParameter{Name: "accents", Type: "[]*colors.Color", Conversion: ConversionSet},
+			"func(s string) (*colors.Color, error) { v := new(colors.Color); err := v.Set(s); return v, err }(value)"},
+	} {
+		t.Run(tt.param.Name, func(t *testing.T) {
+			if got := tt.param.ParserCall("value"); got != tt.want {
+				t.Errorf("ParserCall() = %q, want %q", got, tt.want)
+			}
+			if !tt.param.HasTypedParser() || tt.param.HasPointer() || tt.param.NeedsConversion() {
+				t.Error("a converted parameter should be assigned the parsed value as is")
+			}
+		})
+	}
+	if got := (&FunctionParameter{Type: "app.Level", Conversion: ConversionSet}).TypeDescription(); got != "Level" {
+		t.Errorf("TypeDescription() = %q, want Level", got)
+	}
+	if !(&FunctionParameter{Type: "app.Level"}).NeedsConversion() {
+		t.Error("a named type without a Conversion needs one")
+	}
+	for _, typ := range []string{"app.Level", "uintptr", "[]complex64"} {
+		err := validateParameters([]*FunctionParameter{{Name: "level", Type: typ}}, "app")
+		if err == nil || !strings.Contains(err.Error(), "cannot be parsed from the command line") {
+			t.Errorf("validateParameters(%s) error = %v, want the type rejected", typ, err)
+		}
+	}
+	for _, p := range []*FunctionParameter{
+		{Name: "n", Type: "[]*uint16"},
+		{Name: "ctx", Type: "context.Context", Generator: GeneratorConfig{Type: SourceTypeContext}},
+		{Name: "level", Type: "app.Level", Parser: ParserConfig{Type: ParserTypeCustom, Func: &FuncRef{FunctionName: "ParseLevel"}}},
+	} {
+		if err := validateParameters([]*FunctionParameter{p}, "app"); err != nil {
+			t.Errorf("validateParameters(%s) error = %v", p.Type, err)
+		}
+	}
+}
+
+func TestFlagConstraints(t *testing.T) {
+	params := func() []*FunctionParameter {
+		return []*FunctionParameter{
+			{Name: "name", Type: "string"},
+			{Name: "all", Type: "bool", Conflicts: []string{"name"}},
+			{Name: "cert", Type: "string", Requires: []string{"key"}},
+			{Name: "key", Type: "string", FlagAliases: []string{"tls-key"}},
+			{Name: "target", Type: "string", IsPositional: true, PositionalArgIndex: 1},
+		}
+	}
+	for _, tt := range []struct {
+		name    string
+		edit    func(ps []*FunctionParameter) []*FlagGroup
+		wantErr string
+	}{
+		{"valid", func(ps []*FunctionParameter) []*FlagGroup {
+			return []*FlagGroup{{Kind: FlagGroupOneOf, Names: []string{"name", "all"}}}
+		}, ""},
+		{"flag name", func(ps []*FunctionParameter) []*FlagGroup {
+			ps[2].Requires = []string{"tls-key"}
+			return nil
+		}, ""},
+		{"unknown conflict", func(ps []*FunctionParameter) []*FlagGroup {
+			ps[1].Conflicts = []string{"label"}
+			return nil
+		}, "parameter all conflicts refers to unknown flag label"},
+		{"positional", func(ps []*FunctionParameter) []*FlagGroup {
+			ps[2].Requires = []string{"target"}
+			return nil
+		}, "not a flag of this command"},
+		{"itself", func(ps []*FunctionParameter) []*FlagGroup {
+			ps[0].Conflicts = []string{"name"}
+			return nil
+		}, "cannot conflict with itself"},
+		{"requires and conflicts", func(ps []*FunctionParameter) []*FlagGroup {
+			ps[1].Requires = []string{"name"}
+			return nil
+		}, "both requires and conflicts with name"},
+		{"small group", func(ps []*FunctionParameter) []*FlagGroup {
+			return []*FlagGroup{{Kind: FlagGroupAtLeastOne, Names: []string{"name"}}}
+		}, "AtLeastOne needs at least two flags"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ps := params()
+			groups := tt.edit(ps)
+			err := resolveFlagConstraints(ps, groups, "app")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("resolveFlagConstraints() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("resolveFlagConstraints() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	cmd := &Command{MainCmdName: "app", Parameters: params(), FlagGroups: []*FlagGroup{{Kind: FlagGroupOneOf, Names: []string{"cert", "name"}}}}
+	if err := cmd.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	name, all, cert, key := cmd.Parameters[0], cmd.Parameters[1], cmd.Parameters[2], cmd.Parameters[3]
+	if got := name.UsageDescription(); got != "(conflicts with: --all)" {
+		t.Errorf("UsageDescription() = %q", got)
+	}
+	if got := cert.UsageDescription(); got != "(requires: --tls-key)" {
+		t.Errorf("UsageDescription() = %q", got)
+	}
+	if got := cmd.FlagGroups[0].Summary(); got != "exactly one of --cert, --name" {
+		t.Errorf("Summary() = %q", got)
+	}
+	if !key.NeedsSeen() || !cmd.NeedsSeenFlags() {
+		t.Error("constrained flags should be tracked as seen")
+	}
+	// --all conflicts with --name, which leaves --cert as the one flag of its group.
+	for _, p := range []*FunctionParameter{name, all, cert, key} {
+		if got, want := cmd.SampleOmits(p), p == name; got != want {
+			t.Errorf("SampleOmits(%s) = %v, want %v", p.Name, got, want)
+		}
+	}
+}
+
+func TestValueChecks(t *testing.T) {
+	port := &FunctionParameter{Name: "port", Type: "int", Min: "1", Max: "65535"}
+	want := []ValueCheck{
+		{`seenFlags["port"] && c.port < 1`, `usageErrorf("invalid value %v for flag --port, must be at least 1", c.port)`},
+		{`seenFlags["port"] && c.port > 65535`, `usageErrorf("invalid value %v for flag --port, must be at most 65535", c.port)`},
+	}
+	if got := port.ValueChecks("c.port"); !reflect.DeepEqual(got, want) {
+		t.Errorf("ValueChecks() = %v, want %v", got, want)
+	}
+	name := &FunctionParameter{Name: "name", Type: "*string", Pattern: `^\d+%$`, NonEmpty: true}
+	want = []ValueCheck{
+		{`c.name != nil && *c.name == ""`, `usageErrorf("invalid value %q for flag --name, must not be empty", *c.name)`},
+		{`c.name != nil && !regexp.MustCompile("^\\d+%$").MatchString(*c.name)`, `usageErrorf("invalid value %q for flag --name, must match ^\\d+%%$", *c.name)`},
+	}
+	if got := name.ValueChecks("c.name"); !reflect.DeepEqual(got, want) {
+		t.Errorf("ValueChecks() = %v, want %v", got, want)
+	}
+	if got := name.UsageDescription(); got != `(pattern: ^\d+%$) (nonempty)` {
+		t.Errorf("UsageDescription() = %q", got)
+	}
+	if got := (&FunctionParameter{Name: "timeout", Type: "time.Duration", Max: "1m"}).ValueChecks("v")[0].Invalid; got != `seenFlags["timeout"] && v > time.Duration(60000000000)` {
+		t.Errorf("duration check = %q", got)
+	}
+	if got := (&FunctionParameter{Name: "n", Type: "int", Min: "5"}).SampleValue("1"); got != "5" {
+		t.Errorf("SampleValue() = %q, want the minimum", got)
+	}
+	if got := (&FunctionParameter{Name: "id", Type: "string", Pattern: `^\d+$`, MinLen: 3}).SampleValue("test"); got != "111" {
+		t.Errorf("SampleValue() = %q, want a value matching the checks", got)
+	}
+
+	for _, tt := range []struct {
+		param   *FunctionParameter
+		wantErr string
+	}{
+		{&FunctionParameter{Name: "port", Type: "int", Min: "1", Max: "65535", Default: "8080", HasDefaultValue: true}, ""},
+		{&FunctionParameter{Name: "tags", Type: "[]string", NonEmpty: true, MaxLen: 5}, ""},
+		{&FunctionParameter{Name: "port", Type: "int", Min: "1", Default: "0", HasDefaultValue: true}, "must be at least 1"},
+		{&FunctionParameter{Name: "port", Type: "uint", Min: "-1"}, "invalid bound"},
+		{&FunctionParameter{Name: "port", Type: "int", Min: "10", Max: "1"}, "greater than its max"},
+		{&FunctionParameter{Name: "name", Type: "string", Min: "1"}, "only apply to numbers"},
+		{&FunctionParameter{Name: "n", Type: "int", Pattern: "1"}, "only apply to strings"},
+		{&FunctionParameter{Name: "n", Type: "int", NonEmpty: true}, "nonempty only applies"},
+		{&FunctionParameter{Name: "name", Type: "string", Pattern: "("}, "invalid pattern"},
+		{&FunctionParameter{Name: "name", Type: "string", MinLen: 3, Default: "ab", HasDefaultValue: true}, "must be at least 3 characters"},
+	} {
+		err := validateParameters([]*FunctionParameter{tt.param}, "app")
+		if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("validateParameters(%+v) error = %v, want %q", tt.param, err, tt.wantErr)
+		}
+	}
+}
+
+func TestDeprecations(t *testing.T) {
+	params := func() []*FunctionParameter {
+		return []*FunctionParameter{
+			{Name: "output", Type: "string", FlagAliases: []string{"output", "o"}},
+			{Name: "format", Type: "string", Deprecated: true, Replacement: "o"},
+			{Name: "debug", Type: "bool", Hidden: true},
+			{Name: "target", Type: "string", IsPositional: true, PositionalArgIndex: 1},
+		}
+	}
+	for _, tt := range []struct {
+		name    string
+		edit    func(ps []*FunctionParameter)
+		wantErr string
+	}{
+		{"valid", func(ps []*FunctionParameter) {}, ""},
+		{"unknown replacement", func(ps []*FunctionParameter) { ps[1].Replacement = "out" }, "replaced by unknown flag out"},
+		{"itself", func(ps []*FunctionParameter) { ps[1].Replacement = "format" }, "cannot replace itself"},
+		{"other type", func(ps []*FunctionParameter) { ps[1].Replacement = "debug" }, "of type string cannot be replaced by debug of type bool"},
+		{"positional replacement", func(ps []*FunctionParameter) { ps[1].Replacement = "target" }, "not a flag of this command"},
+		{"hidden positional", func(ps []*FunctionParameter) { ps[3].Hidden = true }, "only flags can be hidden or deprecated"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ps := params()
+			tt.edit(ps)
+			err := resolveDeprecations(ps, "app")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("resolveDeprecations() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("resolveDeprecations() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	root := &Command{MainCmdName: "app"}
+	sc := &SubCommand{Command: root, SubCommandName: "export", SubCommandStructName: "Export", Parameters: params()}
+	root.SubCommands = []*SubCommand{sc}
+	if err := root.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	output, format := sc.Parameters[0], sc.Parameters[1]
+	if format.ReplacementParam != output || !output.NeedsSeen() {
+		t.Error("the replacement of --format should be linked and tracked as seen")
+	}
+	if got, want := format.DeprecationWarning(), "warning: flag --format is deprecated: use --output instead"; got != want {
+		t.Errorf("DeprecationWarning() = %q, want %q", got, want)
+	}
+	if got := format.UsageDescription(); got != "(deprecated: use --output instead)" {
+		t.Errorf("UsageDescription() = %q", got)
+	}
+	for _, group := range sc.ParameterGroups() {
+		for _, p := range group.Parameters {
+			if p.Hidden {
+				t.Errorf("ParameterGroups() lists hidden flag %s", p.Name)
+			}
+		}
+	}
+
+	sc.Hidden, sc.Deprecated = true, true
+	child := &SubCommand{Command: root, Parent: sc, SubCommandName: "all"}
+	sc.SubCommands = []*SubCommand{child}
+	if got, want := sc.DeprecationWarning(), `warning: command "app export" is deprecated`; got != want {
+		t.Errorf("DeprecationWarning() = %q, want %q", got, want)
+	}
+	if !child.IsHidden() {
+		t.Error("a subcommand of a hidden command should be hidden")
+	}
+	if nodes := root.CompletionNodes(); len(nodes) != 1 || strings.Contains(nodes[0].CommandWords(), "export") {
+		t.Errorf("completion should leave out the hidden command, got %d nodes", len(nodes))
+	}
+}
+
+func TestInterspersedFlags(t *testing.T) {
+	root := &Command{MainCmdName: "app", FlagParsing: FlagParsingInterspersed}
+	remote := &SubCommand{Command: root, SubCommandName: "remote"}
+	add := &SubCommand{Command: root, Parent: remote, SubCommandName: "add"}
+	echo := &SubCommand{Command: root, SubCommandName: "echo", FlagParsing: FlagParsingStrict}
+	if !root.InterspersedFlags() || !remote.InterspersedFlags() || !add.InterspersedFlags() {
+		t.Error("subcommands should inherit interspersed flags from the root command")
+	}
+	if echo.InterspersedFlags() {
+		t.Error("a strict subcommand should not accept interspersed flags")
+	}
+	remote.FlagParsing = FlagParsingStrict
+	if add.InterspersedFlags() {
+		t.Error("a subcommand should follow its closest ancestor with a FlagParsing directive")
+	}
+	if (&Command{MainCmdName: "app"}).InterspersedFlags() {
+		t.Error("flag parsing should be strict by default")
+	}
+}
+
+func TestPersistentFlags(t *testing.T) {
+	verbose := &FunctionParameter{Name: "verbose", Type: "bool", FlagAliases: []string{"verbose", "v"}, Persistent: true, DeclaredIn: "app"}
+	color := &FunctionParameter{Name: "color", Type: "bool", DeclaredIn: "app"}
+	org := &FunctionParameter{Name: "org", Type: "string", Persistent: true, DeclaredIn: "users"}
+	root := &Command{MainCmdName: "app", Parameters: []*FunctionParameter{verbose, color}}
+	users := &SubCommand{Command: root, SubCommandName: "users", SubCommandStructName: "Users", Parameters: []*FunctionParameter{org}}
+	fromParent := &FunctionParameter{Name: "verbose", Type: "bool", DeclaredIn: "users"}
+	list := &SubCommand{Command: root, Parent: users, SubCommandName: "list", SubCommandStructName: "List", Parameters: []*FunctionParameter{fromParent}}
+	show := &SubCommand{Command: root, Parent: users, SubCommandName: "show", SubCommandStructName: "Show", Parameters: []*FunctionParameter{
+		{Name: "v", Type: "int", DeclaredIn: "show"},
+	}}
+	root.SubCommands, users.SubCommands = []*SubCommand{users}, []*SubCommand{list, show}
+	root.ResolveInheritance()
+	if err := root.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if flags := show.PersistentFlags(); len(flags) != 1 || flags[0].Parameter != org || flags[0].Owner != "Users" {
+		t.Errorf("PersistentFlags() = %+v, want only --org of Users, as -v is taken", flags)
+	}
+	flags := list.PersistentFlags()
+	if len(flags) != 2 || flags[1].Parameter != verbose || flags[1].Owner != "RootCmd" {
+		t.Fatalf("PersistentFlags() = %+v, want --org of Users and --verbose of RootCmd", flags)
+	}
+	if got := fromParent.ValueFieldName(); got != "RootCmd.verbose" {
+		t.Errorf("ValueFieldName() = %q, want the field of the root command", got)
+	}
+	if got := len(list.ParsedParameters()); got != 3 {
+		t.Errorf("ParsedParameters() has %d parameters, want 3", got)
+	}
+	for _, n := range root.CompletionNodes() {
+		if n.Path == "users list" {
+			if got, want := n.FlagWords(), "--org --verbose -v --help -h"; got != want {
+				t.Errorf("list FlagWords() = %q, want %q", got, want)
+			}
+		}
+	}
+	groups := list.ParameterGroups()
+	last := groups[len(groups)-1]
+	if !last.Inherited || len(last.Parameters) != 2 || last.Parameters[0] != org || last.Parameters[1] != verbose {
+		t.Errorf("ParameterGroups() should end with the inherited flags, got %+v", last)
+	}
+	for _, g := range groups[:len(groups)-1] {
+		for _, p := range g.Parameters {
+			if p == fromParent || p.Persistent {
+				t.Errorf("group %s lists %s, which belongs to the inherited flags", g.CommandName, p.Name)
+			}
+		}
+	}
+
+	for _, tt := range []struct {
+		name    string
+		param   *FunctionParameter
+		wantErr string
+	}{
+		{"positional", &FunctionParameter{Name: "name", Type: "string", IsPositional: true, Persistent: true}, "only flags can be persistent"},
+		{"required", &FunctionParameter{Name: "org", Type: "string", Required: true, Persistent: true}, "cannot be required"},
+		{"constrained", &FunctionParameter{Name: "org", Type: "string", Persistent: true, constrained: true}, "cannot be part of conflicts"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePersistent([]*FunctionParameter{tt.param}, "app")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validatePersistent() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOutput(t *testing.T) {
+	root := &Command{MainCmdName: "app", Parameters: []*FunctionParameter{{Name: "verbose", Type: "bool"}}}
+	list := &SubCommand{Command: root, SubCommandName: "list", SubCommandStructName: "List", ReturnsError: true, ReturnCount: 1}
+	root.SubCommands = []*SubCommand{list}
+	if root.HasOutput() || root.OutputParameter() != nil {
+		t.Fatal("there should be no --output flag when no command returns a value")
+	}
+	list.ReturnCount = 2
+	if !root.HasOutput() {
+		t.Fatal("a command returning (T, error) should add the --output flag")
+	}
+	output := root.OutputParameter()
+	if output != root.OutputParameter() || !output.Persistent || output.Default != OutputText {
+		t.Errorf("OutputParameter() = %+v, want the same persistent flag defaulting to text", output)
+	}
+	if flags := list.PersistentFlags(); len(flags) != 1 || flags[0].Parameter != output || flags[0].Owner != "RootCmd" {
+		t.Errorf("PersistentFlags() = %+v, want --output of RootCmd", flags)
+	}
+	if err := root.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	list.Parameters = []*FunctionParameter{{Name: "format", Type: "string", FlagAliases: []string{"output", "o"}}}
+	if err := root.Validate(); err == nil || !strings.Contains(err.Error(), "reserved for the output format") {
+		t.Errorf("Validate() error = %v, want --output to be reserved", err)
+	}
+}
+
+func TestHooks(t *testing.T) {
+	root := &Command{MainCmdName: "app", Parameters: []*FunctionParameter{{Name: "dsn", Type: "string"}}}
+	root.Before = &Hook{Func: "Open", Params: []HookParam{{IsContext: true}, {Name: "dsn"}}, ReturnsError: true}
+	admin := &SubCommand{Command: root, SubCommandName: "admin", SubCommandStructName: "Admin", Parameters: []*FunctionParameter{{Name: "token", Type: "string"}}}
+	admin.After = &Hook{Func: "Audit", Package: "app", Params: []HookParam{{Name: "token"}}}
+	purge := &SubCommand{Command: root, Parent: admin, SubCommandName: "purge", SubCommandStructName: "AdminPurge", SubCommandFunctionName: "Purge"}
+	admin.SubCommands = []*SubCommand{purge}
+	root.SubCommands = []*SubCommand{admin}
+	if err := root.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	var calls []string
+	for _, l := range purge.Hooks() {
+		for _, c := range []*HookCall{l.Before, l.After} {
+			if c != nil {
+				calls = append(calls, c.Call())
+			}
+		}
+	}
+	want := []string{"Open(c.hookContext, c.RootCmd.dsn)", "app.Audit(c.Admin.token)"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("purge hooks = %q, want %q", calls, want)
+	}
+	if hooks := purge.HookPackages(); len(hooks) != 1 || hooks[0] != admin.After {
+		t.Errorf("HookPackages() = %+v, want the Audit hook", hooks)
+	}
+
+	admin.After.Params = []HookParam{{Name: "force"}}
+	if err := root.Validate(); err == nil || !strings.Contains(err.Error(), "hook parameter force") {
+		t.Errorf("Validate() error = %v, want an unknown hook parameter", err)
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	root := &Command{MainCmdName: "app", ExitCodes: []*ExitCode{
+		{Error: "ErrNotFound", Package: "app", ImportPath: "example.com/app", Code: 3},
+		{Error: "*ConflictError", IsType: true, Package: "app", ImportPath: "example.com/app", Code: 4},
+		{Error: "ErrLocal", ImportPath: "example.com/app/cmd/app", Code: 5},
+	}}
+	if err := root.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range root.ExitCodes {
+		got = append(got, e.Register())
+	}
+	want := []string{"cmd.RegisterExitCode(app.ErrNotFound, 3)", "cmd.RegisterExitCodeAs(new(*app.ConflictError), 4)", "cmd.RegisterExitCode(ErrLocal, 5)"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Register() = %q, want %q", got, want)
+	}
+	if codes := root.ExitCodePackages(); len(codes) != 2 {
+		t.Errorf("ExitCodePackages() = %+v, want the two errors of package app", codes)
+	}
+
+	root.ExitCodes = append(root.ExitCodes, &ExitCode{Error: "ErrNotFound", Package: "app", ImportPath: "example.com/app", Code: 6})
+	if err := root.Validate(); err == nil || !strings.Contains(err.Error(), "given exit codes 3 and 6") {
+		t.Errorf("Validate() error = %v, want conflicting codes", err)
+	}
+	root.ExitCodes = []*ExitCode{{Error: "ErrNotFound", Code: 256}}
+	if err := root.Validate(); err == nil || !strings.Contains(err.Error(), "between 1 and 125") {
+		t.Errorf("Validate() error = %v, want an out of range code", err)
+	}
+}
+
+func TestHelpTopics(t *testing.T) {
+	root := &Command{MainCmdName: "app"}
+	root.SubCommands = []*SubCommand{{Command: root, SubCommandName: "serve", Aliases: []string{"run"}}}
+	root.HelpTopics = []*HelpTopic{{Command: root, Name: "environment", Text: "'quoted\n.dot \\n"}}
+	if err := root.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := root.HelpTopics[0].ManText(), "\\&'quoted\n\\&.dot \\en"; got != want {
+		t.Errorf("ManText() = %q, want %q", got, want)
+	}
+	for _, name := range []string{"serve", "run", "help"} {
+		root.HelpTopics = []*HelpTopic{{Command: root, Name: name}}
+		if err := root.Validate(); err == nil || !strings.Contains(err.Error(), "help topic "+name+" is already") {
+			t.Errorf("Validate() error = %v, want topic %s to clash", err, name)
+		}
+	}
+}
+
+func TestExamples(t *testing.T) {
+	root := &Command{MainCmdName: "app", Examples: []*Example{{Command: "app -v", Words: []string{"app", "-v"}}}}
+	users := &SubCommand{Command: root, SubCommandName: "users", Aliases: []string{"u"}}
+	list := &SubCommand{Command: root, Parent: users, SubCommandName: "list", Examples: []*Example{
+		{Command: "app u list --admins", Words: []string{"app", "u", "list", "--admins"}},
+	}}
+	users.SubCommands = []*SubCommand{list}
+	root.SubCommands = []*SubCommand{users}
+	if err := root.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if got := list.ExampleArgs(list.Examples[0]); !reflect.DeepEqual(got, []string{"--admins"}) {
+		t.Errorf("ExampleArgs() = %q, want [--admins]", got)
+	}
+	if got := root.ExampleArgs(root.Examples[0]); !reflect.DeepEqual(got, []string{"-v"}) {
+		t.Errorf("root ExampleArgs() = %q, want [-v]", got)
+	}
+	if got, want := (&Example{Command: `app --re '\d'`}).ManCommand(), `app \-\-re '\ed'`; got != want {
+		t.Errorf("ManCommand() = %q, want %q", got, want)
+	}
+
+	root.Parameters = []*FunctionParameter{{Name: "verbose", Type: "bool"}, {Name: "region", Type: "string", FlagAliases: []string{"r"}}}
+	list.Examples = []*Example{
+		{Command: "app --verbose -r eu u list --admins", Words: []string{"app", "--verbose", "-r", "eu", "u", "list", "--admins"}},
+		{Command: "app --region=eu users list", Words: []string{"app", "--region=eu", "users", "list"}},
+	}
+	if err := root.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if got := list.ExampleArgs(list.Examples[0]); !reflect.DeepEqual(got, []string{"--admins"}) {
+		t.Errorf("ExampleArgs() after root flags = %q, want [--admins]", got)
+	}
+	if got := list.ExampleArgs(list.Examples[1]); len(got) != 0 {
+		t.Errorf("ExampleArgs() after a root flag with = = %q, want none", got)
+	}
+	list.Examples = []*Example{{Command: "app --verbose extra users list", Words: []string{"app", "--verbose", "extra", "users", "list"}}}
+	if err := root.Validate(); err == nil || !strings.Contains(err.Error(), `does not start with "app users list"`) {
+		t.Errorf("Validate() error = %v, want a boolean flag not to take a value", err)
+	}
+
+	list.Examples = []*Example{{Command: "app --verbse users list", Words: []string{"app", "--verbse", "users", "list"}}}
+	if err := root.Validate(); err == nil || !strings.Contains(err.Error(), `gives --verbse, which is not a flag of "app"`) {
+		t.Errorf("Validate() error = %v, want an unknown root flag to be rejected", err)
+	}
+	list.Examples = []*Example{{Command: "app users --verbose list", Words: []string{"app", "users", "--verbose", "list"}}}
+	if err := root.Validate(); err == nil || !strings.Contains(err.Error(), `gives --verbose, which is not a flag of "app users"`) {
+		t.Errorf("Validate() error = %v, want a root flag that is not persistent to be rejected after users", err)
+	}
+
+	list.Examples = []*Example{{Command: "app users", Words: []string{"app", "users"}}}
+	if err := root.Validate(); err == nil || !strings.Contains(err.Error(), `does not start with "app users list"`) {
+		t.Errorf("Validate() error = %v, want an example of another command", err)
+	}
+	list.Examples = nil
+	users.Examples = []*Example{{Command: "app users list", Words: []string{"app", "users", "list"}}}
+	if err := root.Validate(); err == nil || !strings.Contains(err.Error(), "runs subcommand list") {
+		t.Errorf("Validate() error = %v, want an example running a subcommand", err)
+	}
+}
+
+func TestPrompt(t *testing.T) {
+	name := &FunctionParameter{Name: "name", Type: "string", Prompt: true, PromptLabel: "Project name", Required: true}
+	if !name.Prompts() || name.PromptText() != "Project name: " {
+		t.Errorf("Prompts() = %v, PromptText() = %q", name.Prompts(), name.PromptText())
+	}
+	token := &FunctionParameter{Name: "token", Type: "string", Secret: true}
+	if token.Prompts() {
+		t.Error("an optional secret flag must not be prompted for")
+	}
+	pos := &FunctionParameter{Name: "dir", Type: "string", Prompt: true, IsPositional: true, PositionalArgIndex: 1}
+	if !pos.Prompts() || pos.PromptText() != "dir: " {
+		t.Errorf("positional Prompts() = %v, PromptText() = %q", pos.Prompts(), pos.PromptText())
+	}
+	root := &Command{MainCmdName: "app", SubCommands: []*SubCommand{{SubCommandName: "init", Parameters: []*FunctionParameter{pos}}}}
+	if !root.UsesPrompt() {
+		t.Error("UsesPrompt() = false, want true for a prompted subcommand parameter")
+	}
+
+	for _, tt := range []struct {
+		param   *FunctionParameter
+		wantErr string
+	}{
+		{name, ""},
+		{&FunctionParameter{Name: "tags", Type: "[]string", Prompt: true, Required: true}, "must hold a single value"},
+		{&FunctionParameter{Name: "force", Type: "bool", Secret: true}, "marked (secret) must hold a single value"},
+		{&FunctionParameter{Name: "dir", Type: "string", Prompt: true, IsPositional: true, Default: ".", HasDefaultValue: true}, "never prompted for"},
+	} {
+		err := validateParameters([]*FunctionParameter{tt.param}, "app")
+		if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("validateParameters(%+v) error = %v, want %q", tt.param, err, tt.wantErr)
+		}
+	}
+}
+
+func TestSecret(t *testing.T) {
+	token := &FunctionParameter{Name: "token", Type: "string", Secret: true, Default: "dev-token", HasDefaultValue: true, Description: "Access token"}
+	if got := token.DefaultString(); got != "(default: ********)" {
+		t.Errorf("DefaultString() = %q, want the default masked", got)
+	}
+	if got := token.ManDefault(); got != "********" {
+		t.Errorf("ManDefault() = %q, want the default masked", got)
+	}
+	if got := token.UsageDescription(); got != "Access token (@file or - for stdin)" {
+		t.Errorf("UsageDescription() = %q", got)
+	}
+	if !token.ReadsSecret() {
+		t.Error("ReadsSecret() = false for a secret flag")
+	}
+	if (&FunctionParameter{Name: "pin", Type: "string", Secret: true, IsPositional: true, PositionalArgIndex: 1}).ReadsSecret() {
+		t.Error("ReadsSecret() = true for a positional argument")
+	}
+	root := &Command{MainCmdName: "app", SubCommands: []*SubCommand{{SubCommandName: "deploy", Parameters: []*FunctionParameter{token}}}}
+	if !root.UsesSecretFlags() {
+		t.Error("UsesSecretFlags() = false, want true for a secret subcommand flag")
+	}
+}
@@ -0,0 +1,100 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Formats of the root --output flag, which renders the value a command returns.
+const (
+	OutputText  = "text"
+	OutputJSON  = "json"
+	OutputJSONL = "jsonl"
+	OutputTable = "table"
+)
+
+// OutputFlagName is the root flag that picks the format returned values are written in.
+const OutputFlagName = "output"
+
+// ReturnsValue reports whether the root command function returns a value along with its
+// error, as in func() (T, error).
+func (cmd *Command) ReturnsValue() bool {
+	return cmd.ReturnsError && cmd.ReturnCount == 2
+}
+
+// ReturnsValue reports whether the subcommand function returns a value along with its
+// error, as in func() (T, error).
+func (sc *SubCommand) ReturnsValue() bool {
+	return sc.ReturnsError && sc.ReturnCount == 2
+}
+
+func anyReturnsValue(scs []*SubCommand) bool {
+	for _, sc := range scs {
+		if sc.ReturnsValue() || anyReturnsValue(sc.SubCommands) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasOutput reports whether the generated root command accepts --output, which it does
+// when any of its commands returns a value.
+func (cmd *Command) HasOutput() bool {
+	return cmd != nil && (cmd.ReturnsValue() || anyReturnsValue(cmd.SubCommands))
+}
+
+// OutputParameter describes the --output flag. It is persistent, so every subcommand
+// accepts it and stores it on the root command. It is nil when no command returns a
+// value.
+func (cmd *Command) OutputParameter() *FunctionParameter {
+	if !cmd.HasOutput() {
+		return nil
+	}
+	if cmd.outputParam == nil {
+		cmd.outputParam = &FunctionParameter{
+			Name:            OutputFlagName,
+			Type:            "string",
+			Description:     "Format of the command output",
+			Default:         OutputText,
+			HasDefaultValue: true,
+			Choices:         []string{OutputText, OutputJSON, OutputJSONL, OutputTable},
+			Persistent:      true,
+			DeclaredIn:      cmd.MainCmdName,
+		}
+	}
+	return cmd.outputParam
+}
+
+// validateOutput checks that no flag that would be parsed alongside --output uses its
+// name.
+func validateOutput(cmd *Command) error {
+	if !cmd.HasOutput() {
+		return nil
+	}
+	reserved := func(params []*FunctionParameter, cmdName string) error {
+		for _, p := range params {
+			if !p.IsPositional && !p.HasGenerator() && slices.Contains(flagWords(p), OutputFlagName) {
+				return fmt.Errorf("command %s: flag --%s of parameter %s is reserved for the output format", cmdName, OutputFlagName, p.Name)
+			}
+		}
+		return nil
+	}
+	if err := reserved(cmd.Parameters, cmd.MainCmdName); err != nil {
+		return err
+	}
+	var walk func([]*SubCommand) error
+	walk = func(scs []*SubCommand) error {
+		for _, sc := range scs {
+			if sc.ReturnsValue() {
+				if err := reserved(sc.Parameters, sc.SubCommandName); err != nil {
+					return err
+				}
+			}
+			if err := walk(sc.SubCommands); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return walk(cmd.SubCommands)
+}
@@ -0,0 +1,120 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+)
+
+// PersistentFlag is a persistent flag of an ancestor that a subcommand also parses.
+type PersistentFlag struct {
+	// Parameter is the flag as declared by the ancestor.
+	Parameter *FunctionParameter
+	// Owner is the generated struct of the ancestor, through which the subcommand stores the value.
+	Owner string
+}
+
+// flagWords lists the names the generated parser accepts for a flag: the parameter name
+// and its aliases, without dashes.
+func flagWords(p *FunctionParameter) []string {
+	return append([]string{p.Name}, p.FlagAliases...)
+}
+
+// fromParent reports whether the subcommand takes the parameter from an ancestor with
+// (from parent) rather than declaring it.
+func (sc *SubCommand) fromParent(p *FunctionParameter) bool {
+	return p.InheritedFrom != "" || (p.DeclaredIn != "" && p.DeclaredIn != sc.SubCommandName)
+}
+
+// PersistentFlags returns the persistent flags of the subcommand's ancestors, the closest
+// first. A flag is left out when one of its names is already taken by a flag the
+// subcommand, or a closer ancestor, declares.
+func (sc *SubCommand) PersistentFlags() []PersistentFlag {
+	taken := map[string]bool{}
+	for _, p := range sc.Parameters {
+		if p.IsPositional || p.HasGenerator() || sc.fromParent(p) {
+			continue
+		}
+		for _, w := range flagWords(p) {
+			taken[w] = true
+		}
+	}
+	var flags []PersistentFlag
+	add := func(params []*FunctionParameter, owner string) {
+		for _, p := range params {
+			if !p.Persistent || slices.ContainsFunc(flagWords(p), func(w string) bool { return taken[w] }) {
+				continue
+			}
+			for _, w := range flagWords(p) {
+				taken[w] = true
+			}
+			flags = append(flags, PersistentFlag{Parameter: p, Owner: owner})
+		}
+	}
+	for c := sc.Parent; c != nil; c = c.Parent {
+		add(c.Parameters, c.SubCommandStructName)
+	}
+	// The usage of the root command itself is rendered through a SubCommand without a
+	// name, which inherits nothing.
+	if sc.Command != nil && sc.SubCommandName != "" {
+		add(sc.Command.FlagParameters(), "RootCmd")
+	}
+	return flags
+}
+
+// linkPersistent points the parameters taken from a parent at the persistent flag of
+// that name, if there is one, so that the command reads the value where it is stored.
+func (sc *SubCommand) linkPersistent() {
+	flags := sc.PersistentFlags()
+	for _, p := range sc.Parameters {
+		if !sc.fromParent(p) || p.IsPositional || p.HasGenerator() {
+			continue
+		}
+		name := p.ValueFieldName()
+		for _, f := range flags {
+			if f.Parameter.Name == name {
+				p.InheritedFrom, p.PersistentOwner = name, f.Owner
+				break
+			}
+		}
+	}
+}
+
+// persistentParameters returns the parameters of PersistentFlags.
+func (sc *SubCommand) persistentParameters() []*FunctionParameter {
+	var params []*FunctionParameter
+	for _, f := range sc.PersistentFlags() {
+		params = append(params, f.Parameter)
+	}
+	return params
+}
+
+// ParsedParameters returns the parameters the generated root command parses.
+func (cmd *Command) ParsedParameters() []*FunctionParameter {
+	return cmd.Parameters
+}
+
+// ParsedParameters returns the parameters the generated subcommand parses: its own,
+// followed by the persistent flags of its ancestors.
+func (sc *SubCommand) ParsedParameters() []*FunctionParameter {
+	return append(slices.Clip(sc.Parameters), sc.persistentParameters()...)
+}
+
+// validatePersistent checks that persistent flags are plain flags. A required or
+// constrained flag is checked by the command declaring it before a subcommand gets to
+// parse it, so it cannot be persistent.
+func validatePersistent(params []*FunctionParameter, cmdName string) error {
+	for _, p := range params {
+		if !p.Persistent {
+			continue
+		}
+		switch {
+		case p.IsPositional || p.IsVarArg || p.HasGenerator() || p.InheritedFrom != "":
+			return fmt.Errorf("command %s: only flags can be persistent, not %s", cmdName, p.Name)
+		case p.Required:
+			return fmt.Errorf("command %s: persistent flag %s cannot be required", cmdName, p.Name)
+		case p.constrained:
+			return fmt.Errorf("command %s: persistent flag %s cannot be part of conflicts, requires or flag groups", cmdName, p.Name)
+		}
+	}
+	return nil
+}
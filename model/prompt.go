@@ -0,0 +1,72 @@
+package model
+
+import "fmt"
+
+// Prompts reports whether the generated code asks for the parameter on the terminal
+// when it is missing: it is marked (prompt) or (secret) and must be given, as a flag
+// marked required or a positional argument without a default.
+func (p *FunctionParameter) Prompts() bool {
+	if !p.Prompt && !p.Secret {
+		return false
+	}
+	if p.IsPositional {
+		return !p.HasDefaultValue && !p.IsVarArg
+	}
+	return p.Required
+}
+
+// PromptText is what the generated code writes before reading the parameter: the label
+// of its prompt attribute, or else its name.
+func (p *FunctionParameter) PromptText() string {
+	label := p.PromptLabel
+	if label == "" {
+		label = p.Name
+	}
+	return label + ": "
+}
+
+// validatePrompt checks that a parameter marked (prompt) or (secret) holds a single value
+// typed on one line, and that a prompted positional argument is ever missing.
+func validatePrompt(p *FunctionParameter, cmdName string) error {
+	if !p.Prompt && !p.Secret {
+		return nil
+	}
+	attr := "prompt"
+	if p.Secret {
+		attr = "secret"
+	}
+	if p.HasGenerator() || p.IsVarArg || p.IsSlice() || p.IsMap() || p.Type == "bool" {
+		return fmt.Errorf("command %s: parameter %s marked (%s) must hold a single value, not %s", cmdName, p.Name, attr, p.Type)
+	}
+	if p.Prompt && p.IsPositional && p.HasDefaultValue {
+		return fmt.Errorf("command %s: positional argument %s has a default, so it is never prompted for", cmdName, p.Name)
+	}
+	return nil
+}
+
+// UsesPrompt reports whether any parameter of the command tree is prompted for.
+func (cmd *Command) UsesPrompt() bool {
+	return cmd.anyParameter((*FunctionParameter).Prompts)
+}
+
+// anyParameter reports whether f holds for a parameter of the root or of any subcommand.
+func (cmd *Command) anyParameter(f func(*FunctionParameter) bool) bool {
+	uses := func(ps []*FunctionParameter) bool {
+		for _, p := range ps {
+			if f(p) {
+				return true
+			}
+		}
+		return false
+	}
+	var walk func([]*SubCommand) bool
+	walk = func(scs []*SubCommand) bool {
+		for _, sc := range scs {
+			if uses(sc.Parameters) || walk(sc.SubCommands) {
+				return true
+			}
+		}
+		return false
+	}
+	return uses(cmd.Parameters) || walk(cmd.SubCommands)
+}
@@ -0,0 +1,64 @@
+package model
+
+// Receiver describes the value a method command is called on.
+type Receiver struct {
+	// Name is the receiver variable of the method declaration, e.g. "s". It may be empty.
+	Name string
+	// Type is the receiver's type name, without package or pointer.
+	Type string
+	// Package is the package the type is called through from the generated code, empty
+	// for package main.
+	Package string
+	// Pointer reports whether the method has a pointer receiver.
+	Pointer bool
+	// Constructor is the function of the same package that returns the receiver. The
+	// zero value of Type is used when it is empty.
+	Constructor string
+	// ConstructorReturnsError reports whether Constructor returns (T, error).
+	ConstructorReturnsError bool
+}
+
+// TypeName is the receiver type as written in the generated code.
+func (r *Receiver) TypeName() string {
+	return qualify(r.Package, r.Type)
+}
+
+// ConstructorCall is the call expression that creates the receiver.
+func (r *Receiver) ConstructorCall() string {
+	return qualify(r.Package, r.Constructor) + "()"
+}
+
+// Declaration is the receiver as written in front of the method name, e.g. "(s *Server)".
+func (r *Receiver) Declaration() string {
+	t := r.Type
+	if r.Pointer {
+		t = "*" + t
+	}
+	if r.Name != "" {
+		t = r.Name + " " + t
+	}
+	return "(" + t + ")"
+}
+
+// Callee is the function, or method on the generated recv variable, the command calls.
+func (cmd *Command) Callee() string {
+	if cmd.Receiver != nil {
+		return "recv." + cmd.FunctionName
+	}
+	return qualify(cmd.CallPackage(), cmd.FunctionName)
+}
+
+// Callee is the function, or method on the generated recv variable, the subcommand calls.
+func (sc *SubCommand) Callee() string {
+	if sc.Receiver != nil {
+		return "recv." + sc.SubCommandFunctionName
+	}
+	return qualify(sc.CallPackage(), sc.SubCommandFunctionName)
+}
+
+func qualify(pkg, name string) string {
+	if pkg == "" {
+		return name
+	}
+	return pkg + "." + name
+}
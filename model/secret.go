@@ -0,0 +1,23 @@
+package model
+
+// secretMask stands in for the default of a secret flag in usage output and man pages.
+const secretMask = "********"
+
+// ReadsSecret reports whether the generated parser reads the value of the flag from a
+// file given as @path, or from stdin given as -, as it does for flags marked (secret).
+func (p *FunctionParameter) ReadsSecret() bool {
+	return p.Secret && !p.IsPositional && !p.HasGenerator() && p.InheritedFrom == ""
+}
+
+// ManDefault is the default shown in man pages, masked for a secret flag.
+func (p *FunctionParameter) ManDefault() string {
+	if p.Secret && p.Default != "" {
+		return secretMask
+	}
+	return p.Default
+}
+
+// UsesSecretFlags reports whether any flag of the command tree is read with readSecret.
+func (cmd *Command) UsesSecretFlags() bool {
+	return cmd.anyParameter((*FunctionParameter).ReadsSecret)
+}
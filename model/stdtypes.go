@@ -0,0 +1,338 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"math/big"
+	"net"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Encodings accepted by []byte parameters.
+const (
+	EncodingBase64 = "base64"
+	EncodingHex    = "hex"
+)
+
+// stdlibType describes a standard library type the generated code parses without a
+// custom parser.
+type stdlibType struct {
+	// pkg is the import path of the package declaring the type.
+	pkg string
+	// description names the type in usage and error messages.
+	description string
+	// sample is a value the generated tests pass for the type.
+	sample string
+	// parse returns the parse call for a value expression. It must return (T, error).
+	parse func(p *FunctionParameter, value string) string
+	// check parses a literal default at generation time.
+	check func(p *FunctionParameter, value string) error
+}
+
+var stdlibTypes = map[string]stdlibType{
+	"time.Time": {
+		pkg:         "time",
+		description: "time",
+		parse: func(p *FunctionParameter, value string) string {
+			return fmt.Sprintf("time.Parse(%s, %s)", p.LayoutExpr(), value)
+		},
+		check: func(p *FunctionParameter, value string) error {
+			_, err := time.Parse(p.LayoutValue(), value)
+			return err
+		},
+	},
+	"*url.URL": {
+		pkg:         "net/url",
+		description: "URL",
+		sample:      "https://example.com",
+		parse:       call("url.Parse"),
+		check: func(_ *FunctionParameter, value string) error {
+			_, err := url.Parse(value)
+			return err
+		},
+	},
+	"net.IP": {
+		pkg:         "net",
+		description: "IP address",
+		sample:      "127.0.0.1",
+		parse:       call("parseIP"),
+		check: func(_ *FunctionParameter, value string) error {
+			if net.ParseIP(value) == nil {
+				return fmt.Errorf("invalid IP address %q", value)
+			}
+			return nil
+		},
+	},
+	"netip.Addr": {
+		pkg:         "net/netip",
+		description: "IP address",
+		sample:      "127.0.0.1",
+		parse:       call("netip.ParseAddr"),
+		check: func(_ *FunctionParameter, value string) error {
+			_, err := netip.ParseAddr(value)
+			return err
+		},
+	},
+	"netip.Prefix": {
+		pkg:         "net/netip",
+		description: "IP prefix",
+		sample:      "10.0.0.0/8",
+		parse:       call("netip.ParsePrefix"),
+		check: func(_ *FunctionParameter, value string) error {
+			_, err := netip.ParsePrefix(value)
+			return err
+		},
+	},
+	"*regexp.Regexp": {
+		pkg:         "regexp",
+		description: "regular expression",
+		sample:      "^test$",
+		parse:       call("regexp.Compile"),
+		check: func(_ *FunctionParameter, value string) error {
+			_, err := regexp.Compile(value)
+			return err
+		},
+	},
+	"[]byte": {
+		parse: func(p *FunctionParameter, value string) string {
+			if p.Encoding == EncodingHex {
+				return fmt.Sprintf("hex.DecodeString(%s)", value)
+			}
+			return fmt.Sprintf("base64.StdEncoding.DecodeString(%s)", value)
+		},
+		check: func(p *FunctionParameter, value string) error {
+			var err error
+			if p.Encoding == EncodingHex {
+				_, err = hex.DecodeString(value)
+			} else {
+				_, err = base64.StdEncoding.DecodeString(value)
+			}
+			return err
+		},
+	},
+	"*big.Int": {
+		pkg:         "math/big",
+		description: "integer",
+		sample:      "12345678901234567890",
+		parse:       call("parseBigInt"),
+		check: func(_ *FunctionParameter, value string) error {
+			if _, ok := new(big.Int).SetString(value, 0); !ok {
+				return fmt.Errorf("invalid integer %q", value)
+			}
+			return nil
+		},
+	},
+}
+
+// timeLayouts are the layout constants of the time package a layout attribute may name.
+var timeLayouts = map[string]string{
+	"ANSIC":       time.ANSIC,
+	"UnixDate":    time.UnixDate,
+	"RubyDate":    time.RubyDate,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"Kitchen":     time.Kitchen,
+	"Stamp":       time.Stamp,
+	"StampMilli":  time.StampMilli,
+	"StampMicro":  time.StampMicro,
+	"StampNano":   time.StampNano,
+	"DateTime":    time.DateTime,
+	"DateOnly":    time.DateOnly,
+	"TimeOnly":    time.TimeOnly,
+}
+
+func call(fn string) func(*FunctionParameter, string) string {
+	return func(_ *FunctionParameter, value string) string {
+		return fmt.Sprintf("%s(%s)", fn, value)
+	}
+}
+
+// stdlib returns the standard library type the parameter, or each element of a slice
+// parameter, is parsed as. It is nil for other types and when a custom parser is set.
+func (p *FunctionParameter) stdlib() *stdlibType {
+	if p.HasCustomParser() {
+		return nil
+	}
+	return lookupStdlibType(p.Type)
+}
+
+func lookupStdlibType(t string) *stdlibType {
+	if st, ok := stdlibTypes[t]; ok && t == "[]byte" {
+		return &st
+	}
+	if st, ok := stdlibTypes[strings.TrimPrefix(t, "[]")]; ok {
+		return &st
+	}
+	return nil
+}
+
+// IsStdlibType reports whether the parameter is one of the standard library types, such
+// as time.Time or *url.URL, that the generated code parses itself.
+func (p *FunctionParameter) IsStdlibType() bool {
+	return p.stdlib() != nil
+}
+
+// StdlibSampleValue is the value the generated tests pass to a standard library typed
+// parameter.
+func (p *FunctionParameter) StdlibSampleValue() string {
+	switch {
+	case p.stdlib() == nil:
+		return ""
+	case p.elemType() == "time.Time":
+		return p.SampleValue(time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC).Format(p.LayoutValue()))
+	case p.elemType() == "[]byte" && p.Encoding == EncodingHex:
+		return p.SampleValue("74657374")
+	case p.elemType() == "[]byte":
+		return p.SampleValue("dGVzdA==")
+	}
+	return p.SampleValue(p.stdlib().sample)
+}
+
+// LayoutValue is the layout a time.Time parameter is parsed with, RFC 3339 unless a
+// layout attribute says otherwise.
+func (p *FunctionParameter) LayoutValue() string {
+	name := strings.TrimPrefix(p.Layout, "time.")
+	if layout, ok := timeLayouts[name]; ok {
+		return layout
+	}
+	if p.Layout == "" {
+		return time.RFC3339
+	}
+	return p.Layout
+}
+
+// LayoutExpr is LayoutValue as Go source, naming the time package constant when there is one.
+func (p *FunctionParameter) LayoutExpr() string {
+	name := strings.TrimPrefix(p.Layout, "time.")
+	if p.Layout == "" {
+		name = "RFC3339"
+	}
+	if _, ok := timeLayouts[name]; ok {
+		return "time." + name
+	}
+	return strconv.Quote(p.Layout)
+}
+
+// TypeImports returns the packages the generated code imports for the parameter's type
+// and for parsing and checking its values.
+func (p *FunctionParameter) TypeImports() []string {
+	var imports []string
+	if st := lookupStdlibType(p.Type); st != nil && st.pkg != "" {
+		imports = append(imports, st.pkg)
+	}
+	if p.Pattern != "" {
+		imports = append(imports, "regexp")
+	}
+	if p.elemType() == "[]byte" && !p.HasCustomParser() {
+		if p.Encoding == EncodingHex {
+			imports = append(imports, "encoding/hex")
+		} else {
+			imports = append(imports, "encoding/base64")
+		}
+	}
+	return imports
+}
+
+// StdlibPackagePath returns the import path of a package declaring one of the standard
+// library types, given its name, e.g. net/netip for netip.
+func StdlibPackagePath(name string) string {
+	for _, st := range stdlibTypes {
+		if st.pkg != "" && (st.pkg == name || strings.HasSuffix(st.pkg, "/"+name)) {
+			return st.pkg
+		}
+	}
+	return ""
+}
+
+// UsesParameterType reports whether any parameter of the command tree, or an element of
+// a slice parameter, has type t and is parsed by the generated code.
+func (cmd *Command) UsesParameterType(t string) bool {
+	uses := func(ps []*FunctionParameter) bool {
+		for _, p := range ps {
+			if p.IsStdlibType() && p.elemType() == t {
+				return true
+			}
+		}
+		return false
+	}
+	var walk func([]*SubCommand) bool
+	walk = func(scs []*SubCommand) bool {
+		for _, sc := range scs {
+			if uses(sc.Parameters) || walk(sc.SubCommands) {
+				return true
+			}
+		}
+		return false
+	}
+	return uses(cmd.Parameters) || walk(cmd.SubCommands)
+}
+
+// elemType is the type of a single value: the parameter type, less the slice of a
+// repeatable flag.
+func (p *FunctionParameter) elemType() string {
+	if p.Type == "[]byte" {
+		return p.Type
+	}
+	return strings.TrimPrefix(p.Type, "[]")
+}
+
+func validateStdlibType(p *FunctionParameter, cmdName string) error {
+	t := p.elemType()
+	if p.Layout != "" && t != "time.Time" {
+		return fmt.Errorf("command %s: layout only applies to time.Time parameters, not %s %s", cmdName, p.Name, p.Type)
+	}
+	if p.Encoding != "" {
+		if t != "[]byte" {
+			return fmt.Errorf("command %s: encoding only applies to []byte parameters, not %s %s", cmdName, p.Name, p.Type)
+		}
+		if p.Encoding != EncodingBase64 && p.Encoding != EncodingHex {
+			return fmt.Errorf("command %s: encoding of parameter %s must be %s or %s, not %q", cmdName, p.Name, EncodingBase64, EncodingHex, p.Encoding)
+		}
+	}
+	st := p.stdlib()
+	if st == nil || !p.HasDefaultValue || p.Default == "" || isExpression(p.Default) {
+		return nil
+	}
+	if err := st.check(p, p.Default); err != nil {
+		return fmt.Errorf("command %s: invalid default %q for %s parameter %s: %w", cmdName, p.Default, p.TypeDescription(), p.Name, err)
+	}
+	return nil
+}
+
+// isExpression reports whether a default is Go source, such as time.Now() or
+// net.IPv4zero, rather than a literal value to parse. A selector must name an exported
+// identifier so that a host name like example.com stays a literal.
+func isExpression(s string) bool {
+	expr, err := parser.ParseExpr(s)
+	if err != nil {
+		return false
+	}
+	switch e := expr.(type) {
+	case *ast.CallExpr:
+		return true
+	case *ast.SelectorExpr:
+		return ast.IsExported(e.Sel.Name)
+	}
+	return false
+}
+
+// EncodingName is the encoding a []byte parameter is decoded from.
+func (p *FunctionParameter) EncodingName() string {
+	if p.Encoding == "" {
+		return EncodingBase64
+	}
+	return p.Encoding
+}
@@ -0,0 +1,30 @@
+package model
+
+import "strings"
+
+// SignatureParameters returns the parameters the root function is called with, in
+// declaration order. A struct parameter appears once here, while Parameters holds the
+// flags for its fields.
+func (cmd *Command) SignatureParameters() []*FunctionParameter {
+	if cmd.Signature != nil {
+		return cmd.Signature
+	}
+	return cmd.Parameters
+}
+
+// SignatureParameters returns the parameters the subcommand function is called with.
+func (sc *SubCommand) SignatureParameters() []*FunctionParameter {
+	if sc.Signature != nil {
+		return sc.Signature
+	}
+	return sc.Parameters
+}
+
+// ArgName is the name a positional argument is shown under in usage. A field of a
+// struct parameter uses its flag name rather than the dotted field path.
+func (p *FunctionParameter) ArgName() string {
+	if strings.Contains(p.Name, ".") && len(p.FlagAliases) > 0 {
+		return p.FlagAliases[0]
+	}
+	return p.Name
+}
@@ -0,0 +1,50 @@
+package model
+
+import "strings"
+
+// LongFlagNames returns the long flags the generated root command parses, followed by
+// --help. They are the candidates offered when a flag is mistyped.
+func (cmd *Command) LongFlagNames() []string {
+	return longFlagNames(cmd.FlagParameters())
+}
+
+// LongFlagNames returns the long flags the generated subcommand parses itself, including
+// the persistent flags of its ancestors, followed by --help.
+func (sc *SubCommand) LongFlagNames() []string {
+	return longFlagNames(sc.ParsedParameters())
+}
+
+// HasPositionalParameters reports whether the root command takes positional arguments.
+// Without any, a leftover argument can only be a mistyped subcommand.
+func (cmd *Command) HasPositionalParameters() bool {
+	return hasPositional(cmd.Parameters)
+}
+
+// HasPositionalParameters reports whether the subcommand takes positional arguments.
+func (sc *SubCommand) HasPositionalParameters() bool {
+	return hasPositional(sc.Parameters)
+}
+
+func hasPositional(params []*FunctionParameter) bool {
+	for _, p := range params {
+		if p.IsPositional {
+			return true
+		}
+	}
+	return false
+}
+
+func longFlagNames(params []*FunctionParameter) []string {
+	var names []string
+	for _, p := range params {
+		if p.IsPositional || p.HasGenerator() || p.InheritedFrom != "" || p.Hidden {
+			continue
+		}
+		for _, n := range p.FlagNames() {
+			if strings.HasPrefix(n, "--") {
+				names = append(names, n)
+			}
+		}
+	}
+	return append(names, "--help")
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HelpTopic is a page of guidance that is not a command, shown by `help <topic>`. It is
+// declared on a string constant or variable holding the text.
+type HelpTopic struct {
+	// Command is the root command the topic belongs to.
+	Command *Command `json:"-"`
+	// Name is the word given to help to show the topic.
+	Name string
+	// Description is the one-line summary listed under "Help topics" in usage.
+	Description string
+	// Text is the body of the topic.
+	Text string
+}
+
+// ManName is the name of the topic's man page.
+func (t *HelpTopic) ManName() string {
+	return t.Command.MainCmdName + "-" + t.Name
+}
+
+// ManText is Text escaped for roff: backslashes are doubled up and lines starting with
+// a control character are kept as text.
+func (t *HelpTopic) ManText() string {
+	lines := strings.Split(strings.ReplaceAll(t.Text, `\`, `\e`), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// builtinCommands are the commands every generated root has.
+var builtinCommands = []string{"help", "usage", "version", "completion"}
+
+// validateHelpTopics checks that the topics have distinct names that are not the names of
+// commands, so that help can tell them apart.
+func validateHelpTopics(cmd *Command) error {
+	names := make(map[string]string)
+	for _, b := range builtinCommands {
+		names[b] = "a built-in command"
+	}
+	for _, sc := range cmd.SubCommands {
+		for _, n := range append([]string{sc.SubCommandName}, sc.Aliases...) {
+			names[strings.ToLower(n)] = "a command"
+		}
+	}
+	for _, t := range cmd.HelpTopics {
+		if what, ok := names[t.Name]; ok {
+			return fmt.Errorf("command %s: help topic %s is already %s", cmd.MainCmdName, t.Name, what)
+		}
+		names[t.Name] = "a help topic"
+	}
+	return nil
+}
@@ -0,0 +1,284 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ValueCheck is one condition the generated code checks a parsed value against.
+type ValueCheck struct {
+	// Invalid is a Go expression that is true when the value is rejected.
+	Invalid string
+	// Error is the Go expression of the error returned for a rejected value.
+	Error string
+}
+
+// HasValueChecks reports whether any of min, max, minlen, maxlen, pattern or nonempty
+// is set on the parameter.
+func (p *FunctionParameter) HasValueChecks() bool {
+	return p.Min != "" || p.Max != "" || p.MinLen > 0 || p.MaxLen > 0 || p.Pattern != "" || p.NonEmpty
+}
+
+// isCollection reports whether the parameter holds several values, so that nonempty
+// asks for at least one rather than for a non-empty string.
+func (p *FunctionParameter) isCollection() bool {
+	return p.IsSlice() || p.IsVarArg || p.IsMap()
+}
+
+// NonEmptyCollection reports whether the generated code must reject a slice, map or
+// variadic parameter that ends up with no values.
+func (p *FunctionParameter) NonEmptyCollection() bool {
+	return p.NonEmpty && p.isCollection()
+}
+
+// ValueSource names the parameter in error messages: its flag, or the argument.
+func (p *FunctionParameter) ValueSource() string {
+	if p.IsPositional || p.IsVarArg {
+		return "argument " + p.ArgName()
+	}
+	return "flag " + p.PrimaryFlagName()
+}
+
+// ValueChecks returns the checks of a single value, given by the Go expression v: the
+// field itself, or one element of a slice or variadic parameter. A flag that is neither
+// a pointer nor has a default is only checked when it was set, so that leaving out an
+// optional flag is not an error, except by nonempty, which asks for a value.
+func (p *FunctionParameter) ValueChecks(v string) []ValueCheck {
+	value, nilGuard, seenGuard := v, "", ""
+	if p.HasPointer() {
+		value = "*" + v
+		nilGuard = v + " != nil && "
+	} else if !p.isCollection() && !p.IsPositional && !p.HasDefaultValue {
+		seenGuard = fmt.Sprintf("seenFlags[%q] && ", p.Name)
+	}
+	verb := "%v"
+	if p.IsString() {
+		verb = "%q"
+	}
+	var checks []ValueCheck
+	add := func(guard, invalid, message string) {
+		format := fmt.Sprintf("invalid value %s for %s, %s", verb, p.ValueSource(), strings.ReplaceAll(message, "%", "%%"))
+		checks = append(checks, ValueCheck{
+			Invalid: nilGuard + guard + invalid,
+			Error:   fmt.Sprintf("usageErrorf(%s, %s)", strconv.Quote(format), value),
+		})
+	}
+	if p.Min != "" {
+		add(seenGuard, fmt.Sprintf("%s < %s", value, p.boundExpr(p.Min)), "must be at least "+p.Min)
+	}
+	if p.Max != "" {
+		add(seenGuard, fmt.Sprintf("%s > %s", value, p.boundExpr(p.Max)), "must be at most "+p.Max)
+	}
+	if p.NonEmpty && !p.isCollection() {
+		add("", value+` == ""`, "must not be empty")
+	}
+	if p.MinLen > 0 {
+		add(seenGuard, fmt.Sprintf("len([]rune(%s)) < %d", value, p.MinLen), fmt.Sprintf("must be at least %d characters", p.MinLen))
+	}
+	if p.MaxLen > 0 {
+		add(seenGuard, fmt.Sprintf("len([]rune(%s)) > %d", value, p.MaxLen), fmt.Sprintf("must be at most %d characters", p.MaxLen))
+	}
+	if p.Pattern != "" {
+		add(seenGuard, fmt.Sprintf("!regexp.MustCompile(%s).MatchString(%s)", strconv.Quote(p.Pattern), value), "must match "+p.Pattern)
+	}
+	return checks
+}
+
+// boundExpr writes a min or max bound as Go source comparable with the parameter.
+func (p *FunctionParameter) boundExpr(bound string) string {
+	if p.IsDuration() {
+		d, _ := time.ParseDuration(bound)
+		return fmt.Sprintf("time.Duration(%d)", int64(d))
+	}
+	return bound
+}
+
+// ValueChecksString describes the checks for usage output and man pages.
+func (p *FunctionParameter) ValueChecksString() string {
+	var parts []string
+	if p.Min != "" {
+		parts = append(parts, "(min: "+p.Min+")")
+	}
+	if p.Max != "" {
+		parts = append(parts, "(max: "+p.Max+")")
+	}
+	if p.MinLen > 0 {
+		parts = append(parts, fmt.Sprintf("(minlen: %d)", p.MinLen))
+	}
+	if p.MaxLen > 0 {
+		parts = append(parts, fmt.Sprintf("(maxlen: %d)", p.MaxLen))
+	}
+	if p.Pattern != "" {
+		parts = append(parts, "(pattern: "+p.Pattern+")")
+	}
+	if p.NonEmpty {
+		parts = append(parts, "(nonempty)")
+	}
+	return strings.Join(parts, " ")
+}
+
+var (
+	signedTypes   = []string{"int", "int8", "int16", "int32", "int64"}
+	unsignedTypes = []string{"uint", "uint8", "uint16", "uint32", "uint64"}
+	floatTypes    = []string{"float32", "float64"}
+)
+
+func isOneOf(t string, types ...[]string) bool {
+	for _, ts := range types {
+		for _, candidate := range ts {
+			if t == candidate {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseBound parses a min or max bound, or a literal default compared with one, for the
+// parameter's type.
+func (p *FunctionParameter) parseBound(s string) (float64, error) {
+	t := p.BaseType()
+	switch {
+	case t == "time.Duration":
+		d, err := time.ParseDuration(s)
+		return float64(d), err
+	case isOneOf(t, signedTypes):
+		n, err := strconv.ParseInt(s, 10, 64)
+		return float64(n), err
+	case isOneOf(t, unsignedTypes):
+		n, err := strconv.ParseUint(s, 10, 64)
+		return float64(n), err
+	default:
+		return strconv.ParseFloat(s, 64)
+	}
+}
+
+// CheckBounds reports a min or max that is not a value of the parameter's numeric or
+// duration type. Bounds on other types are reported by validation.
+func (p *FunctionParameter) CheckBounds() error {
+	t := p.BaseType()
+	if p.HasCustomParser() || p.HasTypedParser() || p.IsMap() || !isOneOf(t, signedTypes, unsignedTypes, floatTypes, []string{"time.Duration"}) {
+		return nil
+	}
+	for _, bound := range []struct{ name, value string }{{"min", p.Min}, {"max", p.Max}} {
+		if bound.value == "" {
+			continue
+		}
+		if _, err := p.parseBound(bound.value); err != nil {
+			return fmt.Errorf("parameter %s: %s %q is not a valid %s", p.Name, bound.name, bound.value, t)
+		}
+	}
+	return nil
+}
+
+func validateValueChecks(p *FunctionParameter, cmdName string) error {
+	if !p.HasValueChecks() {
+		return nil
+	}
+	t := p.BaseType()
+	custom := p.HasCustomParser() || p.HasTypedParser() || p.IsMap()
+	if p.Min != "" || p.Max != "" {
+		if custom || !isOneOf(t, signedTypes, unsignedTypes, floatTypes, []string{"time.Duration"}) {
+			return fmt.Errorf("command %s: min and max only apply to numbers and durations, not %s %s", cmdName, p.Name, p.Type)
+		}
+		for _, bound := range []string{p.Min, p.Max} {
+			if _, err := p.parseBound(bound); bound != "" && err != nil {
+				return fmt.Errorf("command %s: invalid bound %q for %s parameter %s", cmdName, bound, t, p.Name)
+			}
+		}
+		if p.Min != "" && p.Max != "" {
+			min, _ := p.parseBound(p.Min)
+			max, _ := p.parseBound(p.Max)
+			if min > max {
+				return fmt.Errorf("command %s: min %s of parameter %s is greater than its max %s", cmdName, p.Min, p.Name, p.Max)
+			}
+		}
+	}
+	if p.MinLen > 0 || p.MaxLen > 0 || p.Pattern != "" {
+		if custom || t != "string" {
+			return fmt.Errorf("command %s: minlen, maxlen and pattern only apply to strings, not %s %s", cmdName, p.Name, p.Type)
+		}
+		if p.MaxLen > 0 && p.MinLen > p.MaxLen {
+			return fmt.Errorf("command %s: minlen %d of parameter %s is greater than its maxlen %d", cmdName, p.MinLen, p.Name, p.MaxLen)
+		}
+		if _, err := regexp.Compile(p.Pattern); err != nil {
+			return fmt.Errorf("command %s: invalid pattern for parameter %s: %w", cmdName, p.Name, err)
+		}
+	}
+	if p.NonEmpty && !p.isCollection() && (custom || t != "string") {
+		return fmt.Errorf("command %s: nonempty only applies to strings, slices and maps, not %s %s", cmdName, p.Name, p.Type)
+	}
+	if p.HasDefaultValue && !p.isCollection() && !isExpression(p.Default) {
+		if err := checkDefault(p, p.Default); err != nil {
+			return fmt.Errorf("command %s: default %q of parameter %s %w", cmdName, p.Default, p.Name, err)
+		}
+	}
+	return nil
+}
+
+// checkDefault applies the parameter's checks to a literal default.
+func checkDefault(p *FunctionParameter, def string) error {
+	if u, err := strconv.Unquote(def); err == nil {
+		def = u
+	}
+	if p.Min != "" || p.Max != "" {
+		v, err := p.parseBound(def)
+		if err != nil {
+			return nil
+		}
+		if min, _ := p.parseBound(p.Min); p.Min != "" && v < min {
+			return fmt.Errorf("must be at least %s", p.Min)
+		}
+		if max, _ := p.parseBound(p.Max); p.Max != "" && v > max {
+			return fmt.Errorf("must be at most %s", p.Max)
+		}
+	}
+	n := utf8.RuneCountInString(def)
+	switch {
+	case p.NonEmpty && def == "":
+		return fmt.Errorf("must not be empty")
+	case p.MinLen > 0 && n < p.MinLen:
+		return fmt.Errorf("must be at least %d characters", p.MinLen)
+	case p.MaxLen > 0 && n > p.MaxLen:
+		return fmt.Errorf("must be at most %d characters", p.MaxLen)
+	case p.Pattern != "" && !regexp.MustCompile(p.Pattern).MatchString(def):
+		return fmt.Errorf("must match %s", p.Pattern)
+	}
+	return nil
+}
+
+// checkedSample adjusts a sample value so that it passes the parameter's checks: a
+// number is moved within min and max, and a string is replaced by the first of a few
+// candidates that fits its length and pattern.
+func (p *FunctionParameter) checkedSample(sample string) string {
+	if p.Min != "" || p.Max != "" {
+		v, err := p.parseBound(sample)
+		if err != nil {
+			return sample
+		}
+		if min, _ := p.parseBound(p.Min); p.Min != "" && v < min {
+			return p.Min
+		}
+		if max, _ := p.parseBound(p.Max); p.Max != "" && v > max {
+			return p.Max
+		}
+		return sample
+	}
+	if p.MinLen == 0 && p.MaxLen == 0 && p.Pattern == "" {
+		return sample
+	}
+	candidates := []string{sample, "test1", "a", "1", "Test", "TEST", "test-1", "test@example.com", "https://example.com"}
+	if p.MinLen > 0 {
+		candidates = append(candidates, strings.Repeat("t", p.MinLen), strings.Repeat("1", p.MinLen))
+	}
+	for _, c := range candidates {
+		if checkDefault(p, c) == nil {
+			return c
+		}
+	}
+	return sample
+}
@@ -160,6 +160,94 @@ func TestParseAttributes(t *testing.T) {
 				HasDefaultValue: true,
 			},
 		},
+		{
+			name:  "Env",
+			attrs: "env: APP_PORT",
+			wantParam: ParsedParam{
+				Env: "APP_PORT",
+			},
+		},
+		{
+			name:  "Env From Name",
+			attrs: "env; required",
+			wantParam: ParsedParam{
+				EnvFromName: true,
+				Required:    true,
+			},
+		},
+		{
+			name:  "Choices",
+			attrs: `choices: json, "yaml", table; required`,
+			wantParam: ParsedParam{
+				Choices:  []string{"json", "yaml", "table"},
+				Required: true,
+			},
+		},
+		{
+			name:  "Conflicts and Requires",
+			attrs: "conflicts: name, --label; requires: key",
+			wantParam: ParsedParam{
+				Conflicts: []string{"name", "label"},
+				Requires:  []string{"key"},
+			},
+		},
+		{
+			name:  "Value Checks",
+			attrs: `min: 1; max: 65535; minlen: 3; maxlen: 8; pattern: "^[a-z]+\d*$"; nonempty`,
+			wantParam: ParsedParam{
+				Min:      "1",
+				Max:      "65535",
+				MinLen:   3,
+				MaxLen:   8,
+				Pattern:  `^[a-z]+\d*$`,
+				NonEmpty: true,
+			},
+		},
+		{
+			name:  "Hidden and Deprecated",
+			attrs: `hidden; deprecated: "use --output; or --json"`,
+			wantParam: ParsedParam{
+				Hidden:          true,
+				Deprecated:      true,
+				DeprecationNote: "use --output; or --json",
+			},
+		},
+		{
+			name:      "Persistent",
+			attrs:     "persistent",
+			wantParam: ParsedParam{Persistent: true},
+		},
+		{
+			name:  "Prompt with Label",
+			attrs: `prompt: "Project name"`,
+			wantParam: ParsedParam{
+				Prompt:      true,
+				PromptLabel: "Project name",
+				Required:    true,
+			},
+		},
+		{
+			name:      "Secret",
+			attrs:     "secret; required",
+			wantParam: ParsedParam{Secret: true, Required: true},
+		},
+		{
+			name:  "Replacement",
+			attrs: "replacement: --output",
+			wantParam: ParsedParam{
+				Deprecated:  true,
+				Replacement: "output",
+			},
+		},
+		{
+			name:  "Map Default with Commas",
+			attrs: `required, default: {a: 1, "b": 2}`,
+			wantParam: ParsedParam{
+				Default:         `{a: 1, "b": 2}`,
+				HasDefaultValue: true,
+				Required:        true,
+			},
+		},
 		{
 			name:  "Mixed Parser with Comma",
 			attrs: `parser: func(a,b); required`,
@@ -212,6 +300,228 @@ func TestParseParamDetails_Integration(t *testing.T) {
 				Description: "Description",
 			},
 		},
+		{
+			name: "Env Middle",
+			text: "--port (env: APP_PORT) Port to listen on",
+			want: ParsedParam{
+				Flags:       []string{"port"},
+				Env:         "APP_PORT",
+				Description: "Port to listen on",
+			},
+		},
+		{
+			name: "Env From Name Middle",
+			text: "--port (default: 80) (env) Port to listen on",
+			want: ParsedParam{
+				Flags:       []string{"port"},
+				Default:     "80",
+				EnvFromName: true,
+				Description: "Port to listen on",
+			},
+		},
+		{
+			name: "Env in the Description",
+			text: "--port Port, also read from (env: PORT) by the proxy",
+			want: ParsedParam{
+				Flags:       []string{"port"},
+				Description: "Port, also read from (env: PORT) by the proxy",
+			},
+		},
+		{
+			name: "Env From Name in the Description",
+			text: "--port Port, unlike the (env) settings",
+			want: ParsedParam{
+				Flags:       []string{"port"},
+				Description: "Port, unlike the (env) settings",
+			},
+		},
+		{
+			name: "Map Default Middle",
+			text: "--limit (default: {cpu: 2, mem: 512}) Resource limits",
+			want: ParsedParam{
+				Flags:       []string{"limit"},
+				Default:     "{cpu: 2, mem: 512}",
+				Description: "Resource limits",
+			},
+		},
+		{
+			name: "Value Checks Middle",
+			text: `--code (pattern: "^(a|b)\d$"; minlen: 2) (nonempty) Code to redeem`,
+			want: ParsedParam{
+				Flags:       []string{"code"},
+				Pattern:     `^(a|b)\d$`,
+				MinLen:      2,
+				NonEmpty:    true,
+				Description: "Code to redeem",
+			},
+		},
+		{
+			name: "Value Checks in the Description",
+			text: "--timeout Timeout in seconds (max: 60 recommended) for most servers",
+			want: ParsedParam{
+				Flags:       []string{"timeout"},
+				Description: "Timeout in seconds (max: 60 recommended) for most servers",
+			},
+		},
+		{
+			name: "Parenthesised Description",
+			text: "--runs Runs to keep (max 5 per day)",
+			want: ParsedParam{
+				Flags:       []string{"runs"},
+				Description: "Runs to keep (max 5 per day)",
+			},
+		},
+		{
+			name: "Choices Middle",
+			text: "--format (choices: json, yaml) Output format",
+			want: ParsedParam{
+				Flags:       []string{"format"},
+				Choices:     []string{"json", "yaml"},
+				Description: "Output format",
+			},
+		},
+		{
+			name: "Choices in the Description",
+			text: "--format Output format, see (choices: below) for more",
+			want: ParsedParam{
+				Flags:       []string{"format"},
+				Description: "Output format, see (choices: below) for more",
+			},
+		},
+		{
+			name: "Layout Middle",
+			text: "--at (layout: 15:04) Time of day",
+			want: ParsedParam{
+				Flags:       []string{"at"},
+				Layout:      "15:04",
+				Description: "Time of day",
+			},
+		},
+		{
+			name: "Conflicts and Requires Middle",
+			text: "--cert (conflicts: all) (requires: key) TLS certificate",
+			want: ParsedParam{
+				Flags:       []string{"cert"},
+				Conflicts:   []string{"all"},
+				Requires:    []string{"key"},
+				Description: "TLS certificate",
+			},
+		},
+		{
+			name: "Requires in the Description",
+			text: "--cert Works best (requires: key) when set, see (conflicts: all) too",
+			want: ParsedParam{
+				Flags:       []string{"cert"},
+				Description: "Works best (requires: key) when set, see (conflicts: all) too",
+			},
+		},
+		{
+			name: "Layout and Encoding in the Description",
+			text: "--at Time, in the (layout: 15:04) of the clock, not (encoding: hex)ed",
+			want: ParsedParam{
+				Flags:       []string{"at"},
+				Description: "Time, in the (layout: 15:04) of the clock, not (encoding: hex)ed",
+			},
+		},
+		{
+			name: "Deprecated Suffix",
+			text: "--format Output format (deprecated; replacement: output) (hidden)",
+			want: ParsedParam{
+				Flags:       []string{"format"},
+				Description: "Output format",
+				Hidden:      true,
+				Deprecated:  true,
+				Replacement: "output",
+			},
+		},
+		{
+			name: "Hidden Middle",
+			text: "--debug -d (hidden) Debug output",
+			want: ParsedParam{
+				Flags:       []string{"debug", "d"},
+				Description: "Debug output",
+				Hidden:      true,
+			},
+		},
+		{
+			name: "Hidden in the Description",
+			text: "--debug Debug output, (hidden) from most users",
+			want: ParsedParam{
+				Flags:       []string{"debug"},
+				Description: "Debug output, (hidden) from most users",
+			},
+		},
+		{
+			name: "Persistent Suffix",
+			text: "--verbose -v Print more (persistent)",
+			want: ParsedParam{
+				Flags:       []string{"verbose", "v"},
+				Description: "Print more",
+				Persistent:  true,
+			},
+		},
+		{
+			name: "Persistent Middle",
+			text: "--verbose (persistent) -v Print more",
+			want: ParsedParam{
+				Flags:       []string{"verbose", "v"},
+				Description: "Print more",
+				Persistent:  true,
+			},
+		},
+		{
+			name: "Persistent in the Description",
+			text: "--verbose Print more, kept (persistent) across runs",
+			want: ParsedParam{
+				Flags:       []string{"verbose"},
+				Description: "Print more, kept (persistent) across runs",
+			},
+		},
+		{
+			name: "Prompt and Secret Middle",
+			text: `--name (prompt: "Project name") -n (secret) Name of the project`,
+			want: ParsedParam{
+				Flags:       []string{"name", "n"},
+				Description: "Name of the project",
+				Required:    true,
+				Prompt:      true,
+				PromptLabel: "Project name",
+				Secret:      true,
+			},
+		},
+		{
+			name: "Prompt in the Description",
+			text: "--name Name to use, or (prompt) for one",
+			want: ParsedParam{
+				Flags:       []string{"name"},
+				Description: "Name to use, or (prompt) for one",
+			},
+		},
+		{
+			name: "Secret in the Description",
+			text: "--key Key to sign with, kept (secret) from logs",
+			want: ParsedParam{
+				Flags:       []string{"key"},
+				Description: "Key to sign with, kept (secret) from logs",
+			},
+		},
+		{
+			name: "Hidden Is Not a Word of the Description",
+			text: "--debug (hidden feature) Debug output",
+			want: ParsedParam{
+				Flags:       []string{"debug"},
+				Description: "(hidden feature) Debug output",
+			},
+		},
+		{
+			name: "Layout and Encoding Suffix",
+			text: `Date and key (layout: "Jan 2, 2006"; encoding: HEX)`,
+			want: ParsedParam{
+				Layout:      "Jan 2, 2006",
+				Encoding:    "hex",
+				Description: "Date and key",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -228,6 +538,33 @@ func TestParseParamDetails_Integration(t *testing.T) {
 			if got.Description != tt.want.Description {
 				t.Errorf("Description = %q, want %q", got.Description, tt.want.Description)
 			}
+			if got.Env != tt.want.Env || got.EnvFromName != tt.want.EnvFromName {
+				t.Errorf("Env = %q/%v, want %q/%v", got.Env, got.EnvFromName, tt.want.Env, tt.want.EnvFromName)
+			}
+			if got.Default != tt.want.Default {
+				t.Errorf("Default = %q, want %q", got.Default, tt.want.Default)
+			}
+			if !reflect.DeepEqual(got.Choices, tt.want.Choices) {
+				t.Errorf("Choices = %q, want %q", got.Choices, tt.want.Choices)
+			}
+			if !reflect.DeepEqual(got.Conflicts, tt.want.Conflicts) || !reflect.DeepEqual(got.Requires, tt.want.Requires) {
+				t.Errorf("Conflicts/Requires = %q/%q, want %q/%q", got.Conflicts, got.Requires, tt.want.Conflicts, tt.want.Requires)
+			}
+			if got.Min != tt.want.Min || got.Max != tt.want.Max || got.MinLen != tt.want.MinLen || got.MaxLen != tt.want.MaxLen {
+				t.Errorf("Min/Max/MinLen/MaxLen = %q/%q/%d/%d, want %q/%q/%d/%d", got.Min, got.Max, got.MinLen, got.MaxLen, tt.want.Min, tt.want.Max, tt.want.MinLen, tt.want.MaxLen)
+			}
+			if got.Layout != tt.want.Layout || got.Encoding != tt.want.Encoding {
+				t.Errorf("Layout/Encoding = %q/%q, want %q/%q", got.Layout, got.Encoding, tt.want.Layout, tt.want.Encoding)
+			}
+			if got.Hidden != tt.want.Hidden || got.Deprecated != tt.want.Deprecated || got.Replacement != tt.want.Replacement {
+				t.Errorf("Hidden/Deprecated/Replacement = %v/%v/%q, want %v/%v/%q", got.Hidden, got.Deprecated, got.Replacement, tt.want.Hidden, tt.want.Deprecated, tt.want.Replacement)
+			}
+			if got.Persistent != tt.want.Persistent {
+				t.Errorf("Persistent = %v, want %v", got.Persistent, tt.want.Persistent)
+			}
+			if got.Prompt != tt.want.Prompt || got.PromptLabel != tt.want.PromptLabel || got.Secret != tt.want.Secret {
+				t.Errorf("Prompt/PromptLabel/Secret = %v/%q/%v, want %v/%q/%v", got.Prompt, got.PromptLabel, got.Secret, tt.want.Prompt, tt.want.PromptLabel, tt.want.Secret)
+			}
 		})
 	}
 }
@@ -1,7 +1,6 @@
 package commentv1
 
 import (
-	"bufio"
 	"fmt"
 	"go/ast"
 	"go/parser"
@@ -12,7 +11,9 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/arran4/go-subcommand/model"
@@ -55,11 +56,41 @@ type CommandTree struct {
 	Description        string
 	ExtendedHelp       string
 	ImportPath         string
+	EnvPrefix          string
+	ConfigFormats      []string
+	FlagGroups         []*model.FlagGroup
+	FlagParsing        string
+	Before             *model.Hook
+	After              *model.Hook
+	ExitCodes          []*model.ExitCode
+	HelpTopics         []*model.HelpTopic
+	Examples           []*model.Example
+	Signature          []*model.FunctionParameter
+	Receiver           *model.Receiver
 }
 
 type CommandsTree struct {
 	Commands    map[string]*CommandTree
 	PackagePath string
+	// StructTypes maps "importPath.TypeName" to the struct types seen so far.
+	StructTypes map[string]*ast.StructType
+	// Funcs maps "importPath.FuncName" to the signatures of the functions seen so far.
+	Funcs map[string]*ast.FuncType
+	// FuncContexts maps "importPath.FuncName" to the name context.Context has in the file
+	// declaring the function, or "" when that file does not import context.
+	FuncContexts map[string]string
+	// Names maps "importPath.Name" to token.TYPE or token.VAR for the package level types
+	// and variables seen so far.
+	Names map[string]token.Token
+}
+
+// addExitCodes adds the exit codes a command of the tree declares, skipping repeats.
+func (ct *CommandTree) addExitCodes(codes []*model.ExitCode) {
+	for _, e := range codes {
+		if !slices.ContainsFunc(ct.ExitCodes, func(o *model.ExitCode) bool { return *o == *e }) {
+			ct.ExitCodes = append(ct.ExitCodes, e)
+		}
+	}
 }
 
 func (cst *CommandsTree) Insert(importPath, packageName, cmdName string, subcommandSequence []string, s *model.SubCommand) {
@@ -109,6 +140,7 @@ func (p *CommentParser) Parse(fsys fs.FS, root string, options *parsers.ParseOpt
 		PackagePath: modPath,
 	}
 
+	var files []sourceFile
 	searchPaths := []string{root}
 	recursive := true
 	if options != nil {
@@ -157,17 +189,7 @@ func (p *CommentParser) Parse(fsys fs.FS, root string, options *parsers.ParseOpt
 			}
 			importPath := path.Join(modPath, dir)
 
-			f, err := fsys.Open(pathStr)
-			if err != nil {
-				return err
-			}
-			defer func() {
-				_ = f.Close()
-			}()
-
-			if err := ParseGoFile(fset, pathStr, importPath, f, rootCommands); err != nil {
-				return err
-			}
+			files = append(files, sourceFile{path: pathStr, importPath: importPath})
 			return nil
 		})
 		if err != nil {
@@ -175,6 +197,27 @@ func (p *CommentParser) Parse(fsys fs.FS, root string, options *parsers.ParseOpt
 		}
 	}
 
+	// Struct parameters and receiver constructors may be declared in any file of their
+	// package, so every declaration is known before the commands are parsed.
+	for _, sf := range files {
+		src, err := fs.ReadFile(fsys, sf.path)
+		if err != nil {
+			return nil, err
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), sf.path, src, parser.SkipObjectResolution|parser.ParseComments)
+		if err != nil {
+			// Reported with its position by ParseGoFile below.
+			continue
+		}
+		rootCommands.addDeclarations(sf.importPath, f)
+	}
+
+	for _, sf := range files {
+		if err := parseSourceFile(fsys, fset, sf, rootCommands); err != nil {
+			return nil, err
+		}
+	}
+
 	d := &model.DataModel{
 		FileSet:     fset,
 		PackageName: "main",
@@ -199,23 +242,70 @@ func (p *CommentParser) Parse(fsys fs.FS, root string, options *parsers.ParseOpt
 			DocStart:           cmdTree.DocStart,
 			DocEnd:             cmdTree.DocEnd,
 			Parameters:         cmdTree.Parameters,
+			Signature:          cmdTree.Signature,
+			Receiver:           cmdTree.Receiver,
 			ReturnsError:       cmdTree.ReturnsError,
 			ReturnCount:        cmdTree.ReturnCount,
 			Description:        cmdTree.Description,
 			ExtendedHelp:       cmdTree.ExtendedHelp,
+			EnvPrefix:          cmdTree.EnvPrefix,
+			ConfigFormats:      cmdTree.ConfigFormats,
+			FlagGroups:         cmdTree.FlagGroups,
+			FlagParsing:        cmdTree.FlagParsing,
+			Before:             cmdTree.Before,
+			ExitCodes:          cmdTree.ExitCodes,
+			After:              cmdTree.After,
+			Examples:           cmdTree.Examples,
 		}
 
+		slices.SortStableFunc(cmdTree.HelpTopics, func(a, b *model.HelpTopic) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+		for _, t := range cmdTree.HelpTopics {
+			t.Command = cmd
+		}
+		cmd.HelpTopics = cmdTree.HelpTopics
+
 		allocator := parsers.NewNameAllocator()
 		subCommands := collectSubCommands(cmd, "", cmdTree.SubCommandTree, nil, allocator)
 		cmd.SubCommands = subCommands
 		commands = append(commands, cmd)
 
+		cmd.ResolveEnv()
+		cmd.ResolveConfig()
 		cmd.ResolveInheritance()
 	}
 	d.Commands = commands
+	if targets := conversionTargets(commands); len(targets) > 0 {
+		if options == nil || options.Dir == "" {
+			t := targets[0]
+			return nil, fmt.Errorf("parameter %s of %s: type %s needs type information to be parsed from the command line, which is only loaded when generating from a directory on disk; set a parser or generate from a directory",
+				t.param.Name, t.function, t.param.Type)
+		}
+		if err := resolveParameterTypes(options.Dir, targets); err != nil {
+			return nil, err
+		}
+	}
 	return d, nil
 }
 
+// sourceFile is a Go file found while walking the search paths.
+type sourceFile struct {
+	path       string
+	importPath string
+}
+
+func parseSourceFile(fsys fs.FS, fset *token.FileSet, sf sourceFile, cmdTree *CommandsTree) error {
+	f, err := fsys.Open(sf.path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	return ParseGoFile(fset, sf.path, sf.importPath, f, cmdTree)
+}
+
 func collectSubCommands(cmd *model.Command, name string, sct *SubCommandTree, parent *model.SubCommand, allocator *parsers.NameAllocator) []*model.SubCommand {
 	var subCommands []*model.SubCommand
 	var subCommandNames []string
@@ -280,17 +370,31 @@ func ParseGoFile(fset *token.FileSet, filename, importPath string, file io.Reade
 		return err
 	}
 
+	cmdTree.addDeclarations(importPath, f)
+	contextType := contextTypeName(f)
+
 	// packageName := f.Name.Name
 	for _, s := range f.Decls {
 		switch s := s.(type) {
-		case *ast.FuncDecl:
-			if s.Recv != nil {
-				continue
+		case *ast.GenDecl:
+			if err := cmdTree.addHelpTopics(fset, s); err != nil {
+				return err
 			}
-			cmdName, subCommandSequence, description, extendedHelp, aliases, parsedParams, ok := ParseSubCommandComments(s.Doc.Text())
+		case *ast.FuncDecl:
+			doc, ok := ParseSubCommandDoc(s.Doc.Text())
 			if !ok {
 				continue
 			}
+			var receiver *model.Receiver
+			if s.Recv != nil {
+				var err error
+				if receiver, err = cmdTree.methodReceiver(importPath, f.Name.Name, s.Recv, doc.Receiver); err != nil {
+					return fmt.Errorf("%s: method %s: %w", fset.Position(s.Pos()), s.Name.Name, err)
+				}
+			} else if doc.Receiver != "" {
+				log.Printf("Warning: Receiver is only used by methods, ignoring it on function %s", s.Name.Name)
+			}
+			cmdName, subCommandSequence, description, extendedHelp, aliases, parsedParams := doc.CmdName, doc.SubCommandSequence, doc.Description, doc.ExtendedHelp, doc.Aliases, doc.Params
 
 			if cmdName == "" && len(subCommandSequence) == 0 {
 				cmdName = parsers.ToKebabCase(s.Name.Name)
@@ -306,7 +410,8 @@ func ParseGoFile(fset *token.FileSet, filename, importPath string, file io.Reade
 				currentCmdName = subCommandSequence[len(subCommandSequence)-1]
 			}
 
-			var params []*model.FunctionParameter
+			var params, signature []*model.FunctionParameter
+			hasStructParam := false
 			usedFlagParams := make(map[string]bool)
 			if s.Type.Params != nil {
 				for _, p := range s.Type.Params.List {
@@ -331,6 +436,32 @@ func ParseGoFile(fset *token.FileSet, filename, importPath string, file io.Reade
 							IsVarArg:   isVarArg,
 							DeclaredIn: currentCmdName,
 						}
+
+						// A context.Context is supplied by the generated command and never
+						// becomes a flag, so it must not claim an entry of the Flags block.
+						if contextType != "" && typeName == contextType && !isVarArg {
+							fp.Type = "context.Context"
+							fp.Generator = model.GeneratorConfig{Type: model.SourceTypeContext}
+							params = append(params, fp)
+							signature = append(signature, fp)
+							continue
+						}
+						// A struct parameter is replaced by its fields, which are configured
+						// by their own comments rather than by the Flags block.
+						if structName, st := cmdTree.structType(importPath, expr); st != nil && !isVarArg {
+							expander := &structExpander{cmdTree: cmdTree, fset: fset, importPath: importPath, cmdName: currentCmdName, seen: map[string]bool{}}
+							fields, err := expander.expand(structName, st, name.Name, "")
+							if err != nil {
+								return fmt.Errorf("error processing parameter %s in function %s: %w", name.Name, s.Name.Name, err)
+							}
+							if f.Name.Name != "main" {
+								fp.Type = f.Name.Name + "." + structName
+							}
+							params = append(params, fields...)
+							signature = append(signature, fp)
+							hasStructParam = true
+							continue
+						}
 						// Extract details from different sources with priority
 						// Priority:
 						// 1. Flags: block in main documentation (Top)
@@ -425,113 +556,13 @@ func ParseGoFile(fset *token.FileSet, filename, importPath string, file io.Reade
 						// Fields are filled from lowest priority up, overwriting if present.
 
 						inherited := false
-
-						// Start with Preceding (3rd)
-						if len(candidates) > 2 {
-							c := candidates[2]
-							if len(c.Flags) > 0 {
-								fp.FlagAliases = c.Flags
-							}
-							if c.HasDefaultValue {
-								fp.Default = c.Default
-								fp.HasDefaultValue = true
-							}
-							if c.Description != "" {
-								fp.Description = c.Description
-							}
-							if c.IsPositional {
-								fp.IsPositional = true
-								fp.PositionalArgIndex = c.PositionalArgIndex
-							}
-							if c.IsVarArg {
-								fp.IsVarArg = true
-								fp.VarArgMin = c.VarArgMin
-								fp.VarArgMax = c.VarArgMax
-							}
-							if c.Inherited {
-								inherited = true
-							}
-							if c.Required {
-								fp.Required = true
-							}
-							if c.Generator.Type != "" {
-								fp.Generator = c.Generator
-							}
-							if c.Parser.Type != "" {
-								fp.Parser = c.Parser
-							}
-						}
-
-						// Merge Inline (2nd)
-						if len(candidates) > 1 {
-							c := candidates[1]
-							if len(c.Flags) > 0 {
-								fp.FlagAliases = c.Flags
-							}
-							if c.HasDefaultValue {
-								fp.Default = c.Default
-								fp.HasDefaultValue = true
-							}
-							if c.Description != "" {
-								fp.Description = c.Description
-							}
-							if c.IsPositional {
-								fp.IsPositional = true
-								fp.PositionalArgIndex = c.PositionalArgIndex
-							}
-							if c.IsVarArg {
-								fp.IsVarArg = true
-								fp.VarArgMin = c.VarArgMin
-								fp.VarArgMax = c.VarArgMax
-							}
-							if c.Inherited {
+						for i := len(candidates) - 1; i >= 0; i-- {
+							if candidates[i].mergeInto(fp) {
 								inherited = true
 							}
-							if c.Required {
-								fp.Required = true
-							}
-							if c.Generator.Type != "" {
-								fp.Generator = c.Generator
-							}
-							if c.Parser.Type != "" {
-								fp.Parser = c.Parser
-							}
 						}
-
-						// Merge Flags Block (Top)
-						if len(candidates) > 0 {
-							c := candidates[0]
-							if len(c.Flags) > 0 {
-								fp.FlagAliases = c.Flags
-							}
-							if c.HasDefaultValue {
-								fp.Default = c.Default
-								fp.HasDefaultValue = true
-							}
-							if c.Description != "" {
-								fp.Description = c.Description
-							}
-							if c.IsPositional {
-								fp.IsPositional = true
-								fp.PositionalArgIndex = c.PositionalArgIndex
-							}
-							if c.IsVarArg {
-								fp.IsVarArg = true
-								fp.VarArgMin = c.VarArgMin
-								fp.VarArgMax = c.VarArgMax
-							}
-							if c.Inherited {
-								inherited = true
-							}
-							if c.Required {
-								fp.Required = true
-							}
-							if c.Generator.Type != "" {
-								fp.Generator = c.Generator
-							}
-							if c.Parser.Type != "" {
-								fp.Parser = c.Parser
-							}
+						if err := checkParsedParams(fp, candidates); err != nil {
+							return fmt.Errorf("%s: function %s: %w", fset.Position(p.Pos()), s.Name.Name, err)
 						}
 
 						if inherited {
@@ -577,6 +608,7 @@ func ParseGoFile(fset *token.FileSet, filename, importPath string, file io.Reade
 						}
 
 						params = append(params, fp)
+						signature = append(signature, fp)
 					}
 				}
 			}
@@ -584,6 +616,10 @@ func ParseGoFile(fset *token.FileSet, filename, importPath string, file io.Reade
 			hasDescription := false
 			var missingDescription []string
 			for _, p := range params {
+				if p.IsContext() || p.IsStream() {
+					// Injected by the generated code and never shown as a flag.
+					continue
+				}
 				if p.Description != "" {
 					hasDescription = true
 				} else {
@@ -597,6 +633,10 @@ func ParseGoFile(fset *token.FileSet, filename, importPath string, file io.Reade
 				log.Printf("Warning: In command '%s' (function %s), the following parameters are missing descriptions while others have them: %s", cmdName, s.Name.Name, strings.Join(missingDescription, ", "))
 			}
 
+			if !hasStructParam {
+				signature = nil
+			}
+
 			returnsError := false
 			returnCount := 0
 			if s.Type.Results != nil {
@@ -606,12 +646,45 @@ func ParseGoFile(fset *token.FileSet, filename, importPath string, file io.Reade
 					} else {
 						returnCount++
 					}
+					// Only the last result can be the error; a value returned before it
+					// is written out by the generated command.
+					returnsError = false
 					if ident, ok := r.Type.(*ast.Ident); ok && ident.Name == "error" {
 						returnsError = true
 					}
 				}
-				if returnCount > 1 {
-					return fmt.Errorf("function %s has multiple return values, which is not implemented yet", s.Name.Name)
+				if returnCount > 2 || (returnCount == 2 && !returnsError) {
+					return fmt.Errorf("function %s has multiple return values; only (T, error) is supported", s.Name.Name)
+				}
+			}
+
+			var before, after *model.Hook
+			for _, h := range []struct {
+				name      string
+				directive string
+				hook      **model.Hook
+			}{{doc.Before, "Before", &before}, {doc.After, "After", &after}} {
+				if h.name == "" {
+					continue
+				}
+				hook, err := cmdTree.hook(importPath, f.Name.Name, h.name)
+				if err != nil {
+					return fmt.Errorf("%s: %s hook of function %s: %w", fset.Position(s.Pos()), h.directive, s.Name.Name, err)
+				}
+				*h.hook = hook
+			}
+
+			for _, e := range doc.ExitCodes {
+				e.ImportPath = importPath
+				if f.Name.Name != "main" {
+					e.Package = f.Name.Name
+				}
+				switch kind := cmdTree.Names[importPath+"."+strings.TrimPrefix(e.Error, "*")]; {
+				case kind == token.TYPE:
+					e.IsType = true
+				case kind == token.VAR && !e.IsType:
+				default:
+					return fmt.Errorf("%s: ExitCodes of function %s: %s is not an error variable or non-generic type of package %s", fset.Position(s.Pos()), s.Name.Name, e.Error, importPath)
 				}
 			}
 
@@ -631,13 +704,29 @@ func ParseGoFile(fset *token.FileSet, filename, importPath string, file io.Reade
 				ct.DocStart = s.Doc.Pos()
 				ct.DocEnd = s.Doc.End()
 				ct.Parameters = params
+				ct.Signature = signature
+				ct.Receiver = receiver
 				ct.ReturnsError = returnsError
 				ct.ReturnCount = returnCount
 				ct.Description = description
 				ct.ExtendedHelp = extendedHelp
+				ct.EnvPrefix = doc.EnvPrefix
+				ct.ConfigFormats = doc.ConfigFormats
+				ct.FlagGroups = doc.FlagGroups
+				ct.FlagParsing = doc.FlagParsing
+				ct.Before, ct.After = before, after
+				ct.addExitCodes(doc.ExitCodes)
+				ct.Examples = doc.Examples
+				if doc.Hidden || doc.Deprecated {
+					log.Printf("Warning: Hidden and Deprecated only apply to subcommands, ignoring them on '%s'", cmdName)
+				}
 				continue
 			}
 
+			if len(doc.ConfigFormats) > 0 {
+				log.Printf("Warning: ConfigFile is only supported on the root command, ignoring it on '%s %s'", cmdName, strings.Join(subCommandSequence, " "))
+			}
+
 			subCommandName := subCommandSequence[len(subCommandSequence)-1]
 			cmdTree.Insert(importPath, f.Name.Name, cmdName, subCommandSequence, &model.SubCommand{
 				SubCommandFunctionName: s.Name.Name,
@@ -646,13 +735,25 @@ func ParseGoFile(fset *token.FileSet, filename, importPath string, file io.Reade
 				SubCommandName:         subCommandName,
 				Aliases:                aliases,
 				// SubCommandStructName is assigned during collection
-				DefinitionFile: filename,
-				DocStart:       s.Doc.Pos(),
-				DocEnd:         s.Doc.End(),
-				Parameters:     params,
-				ReturnsError:   returnsError,
-				ReturnCount:    returnCount,
+				DefinitionFile:  filename,
+				DocStart:        s.Doc.Pos(),
+				DocEnd:          s.Doc.End(),
+				Parameters:      params,
+				Signature:       signature,
+				Receiver:        receiver,
+				ReturnsError:    returnsError,
+				ReturnCount:     returnCount,
+				EnvPrefix:       doc.EnvPrefix,
+				FlagGroups:      doc.FlagGroups,
+				FlagParsing:     doc.FlagParsing,
+				Before:          before,
+				After:           after,
+				Hidden:          doc.Hidden,
+				Deprecated:      doc.Deprecated,
+				DeprecationNote: doc.DeprecationNote,
+				Examples:        doc.Examples,
 			})
+			cmdTree.Commands[cmdName].addExitCodes(doc.ExitCodes)
 		}
 	}
 	return nil
@@ -663,12 +764,54 @@ var (
 	reImplicitCheck   = regexp.MustCompile(`@\d+|\.\.\.`)
 	reImplicitFormat  = regexp.MustCompile(`^(\w+):\s+(.*)$`)
 	reAlias           = regexp.MustCompile(`\((?i:aliases|alias|aka):\s*([^)]+)\)`)
-	reDefaultValue    = regexp.MustCompile(`(?:default:\s*)((?:"[^"]*"|[a-zA-Z_][a-zA-Z0-9_.]*(?:\([^)]*\))?|[^),]+))`)
+	reDefaultValue    = regexp.MustCompile(`(?:default:\s*)((?:\{[^}]*\}|"[^"]*"|[a-zA-Z_][a-zA-Z0-9_.]*(?:\([^)]*\))?|[^),]+))`)
 	rePositionalArg   = regexp.MustCompile(`@(\d+)`)
 	reVarArgRange     = regexp.MustCompile(`(\d+)\.\.\.(\d+)|(\.\.\.)`)
 	reFlag            = regexp.MustCompile(`-[\w-]+`)
+	reChoices         = regexp.MustCompile(`\((?i:choices):\s*([^)]*)\)`)
+	reStream          = regexp.MustCompile(`\((?i:(stdin|stdout|stderr))\)`)
+	reEnvVar          = regexp.MustCompile(`\((?i:env)(?::\s*"?([A-Za-z_][A-Za-z0-9_]*)"?)?\s*\)`)
+	reLayout          = regexp.MustCompile(`\((?i:layout):\s*("[^"]*"|[^)]*)\)`)
+	reEncoding        = regexp.MustCompile(`\((?i:encoding):\s*"?(\w+)"?\s*\)`)
+	reConflicts       = regexp.MustCompile(`\((?i:conflicts):\s*([^)]*)\)`)
+	reRequires        = regexp.MustCompile(`\((?i:requires):\s*([^)]*)\)`)
+	reValueChecks     = regexp.MustCompile(`\(((?i:nonempty\s*;\s*)?(?i:min|max|minlen|maxlen|pattern)\s*:(?:"(?:[^"\\]|\\.)*"|[^)"])*|(?i:nonempty))\)`)
+	reVisibility      = regexp.MustCompile(`\(((?i:hidden|deprecated|replacement)\s*(?:[:;](?:"(?:[^"\\]|\\.)*"|[^)"])*)?)\)`)
+	rePersistent      = regexp.MustCompile(`\((?i:persistent)\)`)
+	rePrompt          = regexp.MustCompile(`\(((?i:prompt|secret)\s*(?:[:;](?:"(?:[^"\\]|\\.)*"|[^)"])*)?)\)`)
+	// reLeadingAttributes matches the text between a parameter name and its description:
+	// flags, positional markers and parenthesised attributes.
+	reLeadingAttributes = regexp.MustCompile(`^(?:\s+|-[\w-]+|@\d+|\d*\.\.\.\d*|\((?:"(?:[^"\\]|\\.)*"|[^()"])*\))*$`)
+	// reTrailingAttributes matches parenthesised attributes following a description.
+	reTrailingAttributes = regexp.MustCompile(`^(?:\s+|\((?:"(?:[^"\\]|\\.)*"|[^()"])*\))*$`)
 )
 
+// takeAttributes removes the matches of re that are in the attribute position of text,
+// before the first word of the description or after its last, and returns their
+// submatches like FindAllStringSubmatch. A match within the description is left as part
+// of it.
+func takeAttributes(re *regexp.Regexp, text string) ([][]string, string) {
+	var matches [][]string
+	var rest strings.Builder
+	end := 0
+	for _, loc := range re.FindAllStringSubmatchIndex(text, -1) {
+		if !reLeadingAttributes.MatchString(text[:loc[0]]) && !reTrailingAttributes.MatchString(text[loc[1]:]) {
+			continue
+		}
+		m := make([]string, len(loc)/2)
+		for i := range m {
+			if loc[2*i] >= 0 {
+				m[i] = text[loc[2*i]:loc[2*i+1]]
+			}
+		}
+		matches = append(matches, m)
+		rest.WriteString(text[end:loc[0]])
+		end = loc[1]
+	}
+	rest.WriteString(text[end:])
+	return matches, rest.String()
+}
+
 type ParsedParam struct {
 	Flags              []string
 	Default            string
@@ -684,22 +827,295 @@ type ParsedParam struct {
 	Generator          model.GeneratorConfig
 	Parser             model.ParserConfig
 	DefaultExpr        *model.FuncRef
-	Order              int `json:"-"`
+	Env                string
+	EnvFromName        bool
+	Choices            []string
+	Layout             string
+	Encoding           string
+	Min                string
+	Max                string
+	MinLen             int
+	MaxLen             int
+	Pattern            string
+	NonEmpty           bool
+	Conflicts          []string
+	Requires           []string
+	Hidden             bool
+	Deprecated         bool
+	DeprecationNote    string
+	Replacement        string
+	Persistent         bool
+	Prompt             bool
+	PromptLabel        string
+	Secret             bool
+	// Invalid describes the attribute values that could not be read. They are reported
+	// with the position of the parameter.
+	Invalid []string
+	Order   int `json:"-"`
+}
+
+// checkParsedParams reports the first attribute value of the candidates that could not
+// be read, or a min or max of fp that is not a value of its type.
+func checkParsedParams(fp *model.FunctionParameter, candidates []ParsedParam) error {
+	for _, c := range candidates {
+		if len(c.Invalid) > 0 {
+			return fmt.Errorf("parameter %s: %s", fp.Name, c.Invalid[0])
+		}
+	}
+	return fp.CheckBounds()
+}
+
+// mergeInto copies the fields set on c over fp. It reports whether c marks the
+// parameter as inherited from a parent command.
+func (c ParsedParam) mergeInto(fp *model.FunctionParameter) bool {
+	if len(c.Flags) > 0 {
+		fp.FlagAliases = c.Flags
+	}
+	if c.HasDefaultValue {
+		fp.Default = c.Default
+		fp.HasDefaultValue = true
+	}
+	if c.Description != "" {
+		fp.Description = c.Description
+	}
+	if c.IsPositional {
+		fp.IsPositional = true
+		fp.PositionalArgIndex = c.PositionalArgIndex
+	}
+	if c.IsVarArg {
+		fp.IsVarArg = true
+		fp.VarArgMin = c.VarArgMin
+		fp.VarArgMax = c.VarArgMax
+	}
+	if c.Required {
+		fp.Required = true
+	}
+	if c.Generator.Type != "" {
+		fp.Generator = c.Generator
+	}
+	if c.Parser.Type != "" {
+		fp.Parser = c.Parser
+	}
+	if c.Env != "" {
+		fp.Env = c.Env
+		fp.EnvFromName = false
+	} else if c.EnvFromName {
+		fp.Env = ""
+		fp.EnvFromName = true
+	}
+	if len(c.Choices) > 0 {
+		fp.Choices = c.Choices
+	}
+	if c.Layout != "" {
+		fp.Layout = c.Layout
+	}
+	if c.Encoding != "" {
+		fp.Encoding = c.Encoding
+	}
+	if c.Min != "" {
+		fp.Min = c.Min
+	}
+	if c.Max != "" {
+		fp.Max = c.Max
+	}
+	if c.MinLen > 0 {
+		fp.MinLen = c.MinLen
+	}
+	if c.MaxLen > 0 {
+		fp.MaxLen = c.MaxLen
+	}
+	if c.Pattern != "" {
+		fp.Pattern = c.Pattern
+	}
+	if c.NonEmpty {
+		fp.NonEmpty = true
+	}
+	if len(c.Conflicts) > 0 {
+		fp.Conflicts = c.Conflicts
+	}
+	if len(c.Requires) > 0 {
+		fp.Requires = c.Requires
+	}
+	if c.Hidden {
+		fp.Hidden = true
+	}
+	if c.Deprecated {
+		fp.Deprecated = true
+		fp.DeprecationNote = c.DeprecationNote
+		fp.Replacement = c.Replacement
+	}
+	if c.Persistent {
+		fp.Persistent = true
+	}
+	if c.Prompt {
+		fp.Prompt = true
+		fp.PromptLabel = c.PromptLabel
+	}
+	if c.Secret {
+		fp.Secret = true
+	}
+	return c.Inherited
 }
 
 var reImplicitParam = regexp.MustCompile(`^([\w]+):\s*(.*)$`)
 
+// SubCommandDoc holds everything ParseSubCommandDoc extracts from a function's doc comment.
+type SubCommandDoc struct {
+	CmdName            string
+	SubCommandSequence []string
+	Description        string
+	ExtendedHelp       string
+	Aliases            []string
+	Params             map[string]ParsedParam
+	// EnvPrefix enables environment variable binding for every flag of the command and its subcommands.
+	EnvPrefix string
+	// ConfigFormats lists the config file formats enabled by a ConfigFile directive.
+	ConfigFormats []string
+	// Receiver names the constructor that provides the receiver of a method command.
+	Receiver string
+	// FlagGroups are the OneOf and AtLeastOne groups of the command's flags.
+	FlagGroups []*model.FlagGroup
+	// FlagParsing is the mode chosen by a FlagParsing directive, if any.
+	FlagParsing string
+	// Before and After name the hook functions of Before and After directives.
+	Before, After string
+	// ExitCodes are the entries of ExitCodes directives.
+	ExitCodes []*model.ExitCode
+	// Hidden leaves the command out of usage output, man pages and completion.
+	Hidden bool
+	// Deprecated marks the command as deprecated, with DeprecationNote as the warning.
+	Deprecated      bool
+	DeprecationNote string
+	// Examples are the lines of the Examples block.
+	Examples []*model.Example
+}
+
+// parseConfigFormats reads the format list of a ConfigFile directive, defaulting to JSON.
+// TOML is accepted as a synonym for the INI subset the generated loader understands.
+func parseConfigFormats(value string) []string {
+	var formats []string
+	for _, f := range strings.Split(value, ",") {
+		f = strings.TrimSpace(f)
+		switch f {
+		case "":
+			continue
+		case "toml":
+			f = model.ConfigFormatINI
+		case model.ConfigFormatJSON, model.ConfigFormatINI:
+		default:
+			log.Printf("Warning: unknown config file format %q, expected json or ini", f)
+			continue
+		}
+		if !slices.Contains(formats, f) {
+			formats = append(formats, f)
+		}
+	}
+	if len(formats) == 0 {
+		formats = []string{model.ConfigFormatJSON}
+	}
+	return formats
+}
+
+// directiveValue reads the value of a directive line. Only a line whose value has the
+// shape valid accepts is a directive, so that help text starting with "After:" or
+// "Receiver:" is left alone.
+func directiveValue(line, lowerLine, directive string, valid func(string) bool) (string, bool) {
+	if !strings.HasPrefix(lowerLine, directive) {
+		return "", false
+	}
+	value := strings.TrimSpace(line[len(directive):])
+	if !valid(value) {
+		return "", false
+	}
+	return value, true
+}
+
+var (
+	reFlagListEntry = regexp.MustCompile(`^-{0,2}\w[\w-]*$`)
+	reExitCodeEntry = regexp.MustCompile(`^\*?\w+(?:\s*=\s*[\w-]*)?$`)
+	reWord          = regexp.MustCompile(`^\w+$`)
+)
+
+// isList reports whether value is a comma separated list whose entries all match re.
+// An empty list is accepted only when allowEmpty is set.
+func isList(re *regexp.Regexp, allowEmpty bool) func(string) bool {
+	return func(value string) bool {
+		if value == "" {
+			return allowEmpty
+		}
+		for _, entry := range strings.Split(value, ",") {
+			if !re.MatchString(strings.TrimSpace(entry)) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// isExitCodeList reports whether value looks like the Error=Code list of an ExitCodes
+// directive. Malformed entries are still read, so that parseExitCodes can warn about them.
+func isExitCodeList(value string) bool {
+	return strings.Contains(value, "=") && isList(reExitCodeEntry, false)(value)
+}
+
+// parseExitCodes reads the Error=Code entries of an ExitCodes directive, warning about
+// malformed ones. An error written as *T names a pointer type.
+func parseExitCodes(value string) []*model.ExitCode {
+	var codes []*model.ExitCode
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, codeStr, found := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		code, err := strconv.Atoi(strings.TrimSpace(codeStr))
+		if !found || err != nil || !token.IsIdentifier(strings.TrimPrefix(name, "*")) {
+			log.Printf("Warning: invalid exit code %q, expected Error=Code", entry)
+			continue
+		}
+		codes = append(codes, &model.ExitCode{Error: name, IsType: strings.HasPrefix(name, "*"), Code: code})
+	}
+	return codes
+}
+
+// parseFlagParsing reads the mode of a FlagParsing directive, warning about unknown ones.
+func parseFlagParsing(value string) string {
+	switch value {
+	case model.FlagParsingStrict, model.FlagParsingInterspersed:
+		return value
+	}
+	log.Printf("Warning: unknown flag parsing %q, expected strict or interspersed", value)
+	return ""
+}
+
+// ParseSubCommandComments is a convenience wrapper around ParseSubCommandDoc returning the most commonly used fields.
 func ParseSubCommandComments(text string) (cmdName string, subCommandSequence []string, description string, extendedHelp string, aliases []string, params map[string]ParsedParam, ok bool) {
-	params = make(map[string]ParsedParam)
-	scanner := bufio.NewScanner(strings.NewReader(text))
+	doc, ok := ParseSubCommandDoc(text)
+	return doc.CmdName, doc.SubCommandSequence, doc.Description, doc.ExtendedHelp, doc.Aliases, doc.Params, ok
+}
+
+// ParseSubCommandDoc parses a function doc comment. ok is false when the comment does not declare a subcommand.
+func ParseSubCommandDoc(text string) (doc SubCommandDoc, ok bool) {
+	var (
+		cmdName            string
+		subCommandSequence []string
+		description        string
+		extendedHelp       string
+		aliases            []string
+	)
+	params := make(map[string]ParsedParam)
+	lines := strings.Split(text, "\n")
 	var extendedHelpLines []string
 
 	inFlagsBlock := false
 	justEnteredFlagsBlock := false
+	inExamplesBlock := false
 	paramOrder := 0
 
-	for scanner.Scan() {
-		line := scanner.Text() // Keep whitespace for indentation check
+	for i, line := range lines {
+		// Keep whitespace for indentation check
+		line = strings.TrimSuffix(line, "\r")
 		trimmedLine := strings.TrimSpace(line)
 
 		if justEnteredFlagsBlock {
@@ -762,7 +1178,68 @@ func ParseSubCommandComments(text string) (cmdName string, subCommandSequence []
 			continue
 		}
 
+		if inExamplesBlock {
+			if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+				if example, err := parseExample(trimmedLine); err != nil {
+					log.Printf("Warning: ignoring example %q of command '%s': %v", trimmedLine, cmdName, err)
+				} else {
+					doc.Examples = append(doc.Examples, example)
+				}
+				continue
+			}
+			inExamplesBlock = false
+		}
+
 		lowerTrimmedLine := strings.ToLower(trimmedLine)
+		if strings.HasPrefix(lowerTrimmedLine, DirectiveEnvPrefix) {
+			doc.EnvPrefix = strings.TrimSpace(trimmedLine[len(DirectiveEnvPrefix):])
+			continue
+		}
+		if name, ok := directiveValue(trimmedLine, lowerTrimmedLine, DirectiveReceiver, token.IsIdentifier); ok {
+			doc.Receiver = name
+			continue
+		}
+		if names, ok := directiveValue(trimmedLine, lowerTrimmedLine, DirectiveOneOf, isList(reFlagListEntry, false)); ok {
+			doc.FlagGroups = append(doc.FlagGroups, &model.FlagGroup{Kind: model.FlagGroupOneOf, Names: parseFlagList(names)})
+			continue
+		}
+		if names, ok := directiveValue(trimmedLine, lowerTrimmedLine, DirectiveAtLeastOne, isList(reFlagListEntry, false)); ok {
+			doc.FlagGroups = append(doc.FlagGroups, &model.FlagGroup{Kind: model.FlagGroupAtLeastOne, Names: parseFlagList(names)})
+			continue
+		}
+		if name, ok := directiveValue(trimmedLine, lowerTrimmedLine, DirectiveBefore, token.IsIdentifier); ok {
+			doc.Before = name
+			continue
+		}
+		if name, ok := directiveValue(trimmedLine, lowerTrimmedLine, DirectiveAfter, token.IsIdentifier); ok {
+			doc.After = name
+			continue
+		}
+		if codes, ok := directiveValue(trimmedLine, lowerTrimmedLine, DirectiveExitCodes, isExitCodeList); ok {
+			doc.ExitCodes = append(doc.ExitCodes, parseExitCodes(codes)...)
+			continue
+		}
+		if mode, ok := directiveValue(trimmedLine, lowerTrimmedLine, DirectiveFlagParsing, reWord.MatchString); ok {
+			doc.FlagParsing = parseFlagParsing(strings.ToLower(mode))
+			continue
+		}
+		if lowerTrimmedLine == DirectiveHidden {
+			doc.Hidden = true
+			continue
+		}
+		// The note of a Deprecated line must end its paragraph, so that help text running
+		// on from a line starting with "Deprecated:" is left alone.
+		if note, ok := directiveValue(trimmedLine, lowerTrimmedLine, DirectiveDeprecated, func(string) bool {
+			return i+1 == len(lines) || strings.TrimSpace(lines[i+1]) == ""
+		}); ok {
+			doc.Deprecated = true
+			doc.DeprecationNote = note
+			continue
+		}
+		if formats, ok := directiveValue(trimmedLine, lowerTrimmedLine, DirectiveConfigFile, isList(reWord, true)); ok {
+			doc.ConfigFormats = parseConfigFormats(strings.ToLower(formats))
+			continue
+		}
 		if strings.HasPrefix(lowerTrimmedLine, DirectiveAliasesPrefix) || strings.HasPrefix(lowerTrimmedLine, DirectiveAliasPrefix) {
 			lineParts := strings.SplitN(trimmedLine, ":", 2)
 			if len(lineParts) > 1 {
@@ -780,6 +1257,13 @@ func ParseSubCommandComments(text string) (cmdName string, subCommandSequence []
 		if trimmedLine == DirectiveFlags {
 			inFlagsBlock = true
 			justEnteredFlagsBlock = true
+			inExamplesBlock = false
+			continue
+		}
+
+		if trimmedLine == DirectiveExamples {
+			inExamplesBlock = true
+			inFlagsBlock = false
 			continue
 		}
 
@@ -850,19 +1334,25 @@ func ParseSubCommandComments(text string) (cmdName string, subCommandSequence []
 		}
 	}
 	extendedHelp = strings.TrimSpace(strings.Join(extendedHelpLines, "\n"))
+	doc.CmdName = cmdName
+	doc.SubCommandSequence = subCommandSequence
+	doc.Description = description
+	doc.ExtendedHelp = extendedHelp
+	doc.Aliases = aliases
+	doc.Params = params
 	return
 }
 
 func isLikelyAttribute(attrStr string) bool {
 	lower := strings.ToLower(attrStr)
-	knownKVs := []string{"generator:", "parser:", "default:", "alias:", "aliases:", "aka:"}
+	knownKVs := []string{"generator:", "parser:", "default:", "alias:", "aliases:", "aka:", "env:", "choices:", "layout:", "encoding:", "conflicts:", "requires:", "min:", "max:", "minlen:", "maxlen:", "pattern:", "deprecated:", "replacement:", "prompt:"}
 	for _, k := range knownKVs {
 		if strings.Contains(lower, k) {
 			return true
 		}
 	}
 
-	knownSingles := []string{"required", "inherited", "from parent"}
+	knownSingles := []string{"required", "inherited", "from parent", "env", "stdin", "stdout", "stderr", "nonempty", "hidden", "deprecated", "persistent", "prompt", "secret"}
 	parts := splitSafe(lower, ';')
 	useComma := false
 	for _, part := range parts {
@@ -941,6 +1431,8 @@ func splitSafe(s string, sep rune) []string {
 
 	for _, r := range s {
 		if escaped {
+			// Keep the backslash: it may belong to a value such as a regular expression.
+			current.WriteRune('\\')
 			current.WriteRune(r)
 			escaped = false
 			continue
@@ -954,9 +1446,9 @@ func splitSafe(s string, sep rune) []string {
 		}
 		if !inQuote {
 			switch r {
-			case '(':
+			case '(', '{':
 				depth++
-			case ')':
+			case ')', '}':
 				depth--
 			}
 		}
@@ -1081,8 +1573,105 @@ func parseAttributes(attrs string, p *ParsedParam) {
 			}
 		case AttributeFromParent, AttributeInherited:
 			p.Inherited = true
+		case AttributeChoices:
+			p.Choices = parseChoices(val)
+		case AttributeStdin, AttributeStdout, AttributeStderr:
+			p.Generator = model.GeneratorConfig{Type: model.SourceType(key)}
+		case AttributeEnv:
+			if val != "" {
+				p.Env = strings.Trim(val, "\"")
+			} else {
+				p.EnvFromName = true
+			}
+		case AttributeLayout:
+			p.Layout = unquoteAttribute(val)
+		case AttributeEncoding:
+			p.Encoding = strings.ToLower(unquoteAttribute(val))
+		case AttributeMin:
+			p.Min = val
+		case AttributeMax:
+			p.Max = val
+		case AttributeMinLen, AttributeMaxLen:
+			n, err := strconv.Atoi(val)
+			if err != nil || n <= 0 {
+				p.Invalid = append(p.Invalid, fmt.Sprintf("%s %q is not a positive integer", key, val))
+			} else if key == AttributeMinLen {
+				p.MinLen = n
+			} else {
+				p.MaxLen = n
+			}
+		case AttributePattern:
+			p.Pattern = unquotePattern(val)
+		case AttributeNonEmpty:
+			p.NonEmpty = true
+		case AttributeConflicts:
+			p.Conflicts = parseFlagList(val)
+		case AttributeRequires:
+			p.Requires = parseFlagList(val)
+		case AttributeHidden:
+			p.Hidden = true
+		case AttributeDeprecated:
+			p.Deprecated = true
+			p.DeprecationNote = unquoteAttribute(val)
+		case AttributeReplacement:
+			p.Deprecated = true
+			p.Replacement = strings.TrimLeft(val, "-")
+		case AttributePersistent:
+			p.Persistent = true
+		case AttributePrompt:
+			p.Prompt = true
+			p.Required = true
+			p.PromptLabel = unquoteAttribute(val)
+		case AttributeSecret:
+			p.Secret = true
+		}
+	}
+}
+
+// unquoteAttribute removes the double quotes around an attribute value, which protect
+// separators such as the comma in a layout like "Jan 2, 2006".
+func unquoteAttribute(val string) string {
+	if u, err := strconv.Unquote(val); err == nil {
+		return u
+	}
+	return val
+}
+
+// unquotePattern removes the quotes around a regular expression. Unlike a Go string, its
+// backslashes are kept as written, so "^\d+$" is the pattern ^\d+$; only \" stands for a quote.
+func unquotePattern(val string) string {
+	if len(val) >= 2 && strings.HasPrefix(val, `"`) && strings.HasSuffix(val, `"`) {
+		return strings.ReplaceAll(val[1:len(val)-1], `\"`, `"`)
+	}
+	return unquoteAttribute(val)
+}
+
+// parseFlagList splits a comma separated list of parameter or flag names, dropping the
+// dashes a flag may be written with.
+func parseFlagList(val string) []string {
+	var names []string
+	for _, n := range strings.Split(val, ",") {
+		n = strings.TrimLeft(strings.TrimSpace(n), "-")
+		if n != "" {
+			names = append(names, n)
 		}
 	}
+	return names
+}
+
+// parseChoices splits a comma separated choices list, unquoting quoted entries.
+func parseChoices(val string) []string {
+	var choices []string
+	for _, c := range splitSafe(val, ',') {
+		c = strings.TrimSpace(c)
+		if unquoted, err := strconv.Unquote(c); err == nil {
+			c = unquoted
+		}
+		if c != "" {
+			choices = append(choices, c)
+		}
+	}
+	return choices
 }
 
 func parseParamDetails(text string) ParsedParam {
@@ -1098,6 +1687,65 @@ func parseParamDetails(text string) ParsedParam {
 		text = strings.ReplaceAll(text, "(from parent)", "")
 	}
 
+	env, text := takeAttributes(reEnvVar, text)
+	if len(env) > 0 {
+		if m := env[0]; m[1] != "" {
+			p.Env = m[1]
+		} else {
+			p.EnvFromName = true
+		}
+	}
+
+	choices, text := takeAttributes(reChoices, text)
+	if len(choices) > 0 {
+		p.Choices = parseChoices(choices[0][1])
+	}
+
+	layouts, text := takeAttributes(reLayout, text)
+	if len(layouts) > 0 {
+		p.Layout = unquoteAttribute(strings.TrimSpace(layouts[0][1]))
+	}
+
+	encodings, text := takeAttributes(reEncoding, text)
+	if len(encodings) > 0 {
+		p.Encoding = strings.ToLower(encodings[0][1])
+	}
+
+	checks, text := takeAttributes(reValueChecks, text)
+	for _, m := range checks {
+		parseAttributes(m[1], &p)
+	}
+
+	visibility, text := takeAttributes(reVisibility, text)
+	for _, m := range visibility {
+		parseAttributes(m[1], &p)
+	}
+
+	if persistent, rest := takeAttributes(rePersistent, text); len(persistent) > 0 {
+		p.Persistent = true
+		text = rest
+	}
+
+	prompts, text := takeAttributes(rePrompt, text)
+	for _, m := range prompts {
+		parseAttributes(m[1], &p)
+	}
+
+	conflicts, text := takeAttributes(reConflicts, text)
+	if len(conflicts) > 0 {
+		p.Conflicts = parseFlagList(conflicts[0][1])
+	}
+
+	requires, text := takeAttributes(reRequires, text)
+	if len(requires) > 0 {
+		p.Requires = parseFlagList(requires[0][1])
+	}
+
+	streams, text := takeAttributes(reStream, text)
+	if len(streams) > 0 {
+		p.Generator = model.GeneratorConfig{Type: model.SourceType(strings.ToLower(streams[0][1]))}
+	}
+
 	loc := reDefaultValue.FindStringSubmatchIndex(text)
 	if loc != nil {
 		p.Default = strings.TrimSpace(text[loc[2]:loc[3]])
@@ -1170,6 +1818,24 @@ func parseParamDetails(text string) ParsedParam {
 	return p
 }
 
+// contextTypeName returns how the file refers to context.Context, taking import
+// aliases into account, or "" when the context package is not imported.
+func contextTypeName(f *ast.File) string {
+	for _, imp := range f.Imports {
+		if strings.Trim(imp.Path.Value, `"`) != "context" {
+			continue
+		}
+		if imp.Name == nil {
+			return "context.Context"
+		}
+		if imp.Name.Name == "_" || imp.Name.Name == "." {
+			continue
+		}
+		return imp.Name.Name + ".Context"
+	}
+	return ""
+}
+
 func formatType(expr ast.Expr) (string, error) {
 	switch t := expr.(type) {
 	case *ast.Ident:
@@ -1192,6 +1858,16 @@ func formatType(expr ast.Expr) (string, error) {
 			return "", err
 		}
 		return "[]" + s, nil
+	case *ast.MapType:
+		k, err := formatType(t.Key)
+		if err != nil {
+			return "", err
+		}
+		v, err := formatType(t.Value)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("map[%s]%s", k, v), nil
 	case *ast.Ellipsis:
 		s, err := formatType(t.Elt)
 		if err != nil {
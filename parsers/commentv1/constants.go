@@ -8,6 +8,13 @@ const (
 	//     ...
 	DirectiveFlags = "Flags:"
 
+	// DirectiveExamples is the marker for the start of a block of example command lines,
+	// each indented and optionally followed by a # comment explaining it.
+	// Example:
+	//   Examples:
+	//     app greet --name Bob  # Greet Bob
+	DirectiveExamples = "Examples:"
+
 	// DirectiveIsSubcommand is the marker to indicate that a function is a subcommand.
 	// Example:
 	//   // MyCommand is a subcommand of ParentCommand ...
@@ -22,6 +29,72 @@ const (
 	// Example:
 	//   aliases: mycmd, cmd
 	DirectiveAliasesPrefix = "aliases:"
+
+	// DirectiveEnvPrefix is the prefix that binds every flag of a command, and of its
+	// subcommands, to an environment variable named <prefix>_<FLAG_NAME>.
+	// Example:
+	//   EnvPrefix: APP
+	DirectiveEnvPrefix = "envprefix:"
+
+	// DirectiveConfigFile adds a --config flag to the root command. The value lists the
+	// accepted formats, json (the default) and ini, which also reads simple TOML files.
+	// Example:
+	//   ConfigFile: json, ini
+	DirectiveConfigFile = "configfile:"
+
+	// DirectiveReceiver names the function that creates the receiver of a method
+	// command. Without it the method is called on the receiver type's zero value.
+	// Example:
+	//   Receiver: NewServer
+	DirectiveReceiver = "receiver:"
+
+	// DirectiveOneOf lists flags of which exactly one must be given. A command may
+	// declare several groups.
+	// Example:
+	//   OneOf: json, yaml, table
+	DirectiveOneOf = "oneof:"
+
+	// DirectiveAtLeastOne lists flags of which at least one must be given.
+	// Example:
+	//   AtLeastOne: name, all
+	DirectiveAtLeastOne = "atleastone:"
+
+	// DirectiveFlagParsing chooses how flags are read: strict, the default, stops at the
+	// first positional argument, while interspersed also reads flags that follow
+	// positional arguments. Subcommands inherit it unless they set their own.
+	// Example:
+	//   FlagParsing: interspersed
+	DirectiveFlagParsing = "flagparsing:"
+
+	// DirectiveBefore names a function of the same package to run before the action of
+	// the command and of every command below it, the root's first.
+	// Example:
+	//   Before: OpenDB
+	DirectiveBefore = "before:"
+
+	// DirectiveAfter names a function of the same package to run after the action of the
+	// command and of every command below it, the root's last, even when the action fails.
+	// Example:
+	//   After: CloseDB
+	DirectiveAfter = "after:"
+
+	// DirectiveExitCodes maps errors of the same package to the exit code of the generated
+	// command: a sentinel error is matched with errors.Is, a type with errors.As.
+	// Example:
+	//   ExitCodes: ErrNotFound=3, *ConflictError=4
+	DirectiveExitCodes = "exitcodes:"
+
+	// DirectiveHidden, on a line of its own, leaves a subcommand out of usage output,
+	// man pages and completion. It still runs when invoked.
+	// Example:
+	//   Hidden
+	DirectiveHidden = "hidden"
+
+	// DirectiveDeprecated marks a subcommand as deprecated. Running it prints the rest
+	// of the line as a warning. The line must end its paragraph.
+	// Example:
+	//   Deprecated: use "app get" instead
+	DirectiveDeprecated = "deprecated:"
 )
 
 // Prefixes used to identify parameter definitions in comments.
@@ -74,4 +147,90 @@ const (
 	// AttributeAka is an alias for AttributeAliases.
 	// Usage: (aka: f)
 	AttributeAka = "aka"
+
+	// AttributeEnv binds a flag to an environment variable that is read when the flag is absent.
+	// Without a value the name is derived from the flag name and any EnvPrefix.
+	// Usage: (env: APP_PORT) or (env)
+	AttributeEnv = "env"
+
+	// AttributeChoices restricts a flag or positional argument to a fixed set of values.
+	// Usage: (choices: json, yaml, table)
+	AttributeChoices = "choices"
+
+	// AttributeStdin, AttributeStdout and AttributeStderr wire an io.Reader or io.Writer
+	// parameter to the command's input, output or error stream instead of a flag.
+	// Usage: (stdout)
+	AttributeStdin  = "stdin"
+	AttributeStdout = "stdout"
+	AttributeStderr = "stderr"
+
+	// AttributeLayout sets the layout a time.Time parameter is parsed with, either written
+	// out or as the name of a time package constant. It defaults to RFC3339.
+	// Usage: (layout: 2006-01-02) or (layout: DateTime)
+	AttributeLayout = "layout"
+
+	// AttributeEncoding selects how a []byte parameter is written: base64 (the default) or hex.
+	// Usage: (encoding: hex)
+	AttributeEncoding = "encoding"
+
+	// AttributeMin and AttributeMax bound a number or time.Duration.
+	// Usage: (min: 1; max: 65535)
+	AttributeMin = "min"
+	AttributeMax = "max"
+
+	// AttributeMinLen and AttributeMaxLen bound the length of a string in characters.
+	// Usage: (minlen: 3; maxlen: 64)
+	AttributeMinLen = "minlen"
+	AttributeMaxLen = "maxlen"
+
+	// AttributePattern is a regular expression a string must match. Quote it when it
+	// contains separators or parentheses.
+	// Usage: (pattern: "^[a-z]+$")
+	AttributePattern = "pattern"
+
+	// AttributeNonEmpty rejects an empty string, or a slice or map without entries.
+	// Usage: (nonempty)
+	AttributeNonEmpty = "nonempty"
+
+	// AttributeConflicts names flags of the same command that cannot be given together
+	// with this one.
+	// Usage: (conflicts: name, label)
+	AttributeConflicts = "conflicts"
+
+	// AttributeRequires names flags of the same command that must be given whenever this
+	// one is.
+	// Usage: (requires: key)
+	AttributeRequires = "requires"
+
+	// AttributeHidden leaves a flag out of usage output, man pages and completion. It is
+	// still parsed.
+	// Usage: (hidden)
+	AttributeHidden = "hidden"
+
+	// AttributeDeprecated prints a warning, with the optional note, when the flag is given.
+	// Usage: (deprecated) or (deprecated: "use --output instead")
+	AttributeDeprecated = "deprecated"
+
+	// AttributeReplacement names the flag of the same command that a deprecated flag was
+	// replaced by. The value of the deprecated flag is passed on to it unless it is given
+	// too, and the flag is marked deprecated when no note says otherwise.
+	// Usage: (deprecated; replacement: output)
+	AttributeReplacement = "replacement"
+
+	// AttributePersistent makes a flag of a root or intermediate command accepted by every
+	// command below it, wherever it appears on the command line.
+	// Usage: (persistent)
+	AttributePersistent = "persistent"
+
+	// AttributePrompt makes a flag required and, when it is missing and stdin is a
+	// terminal, asks for it with the optional label. On a positional argument without a
+	// default it asks for the argument instead.
+	// Usage: (prompt) or (prompt: "Project name")
+	AttributePrompt = "prompt"
+
+	// AttributeSecret turns off echo while a required value is prompted for, masks the
+	// default in help, and lets the flag be given as @path or - to read it from a file
+	// or stdin.
+	// Usage: (secret) or (secret; required)
+	AttributeSecret = "secret"
 )
@@ -0,0 +1,65 @@
+package commentv1
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/arran4/go-subcommand/model"
+)
+
+// parseExample reads a line of an Examples block. The command line is split into words
+// as a shell would: quotes group words and a backslash escapes the next character. An
+// unquoted # starting a word begins the explanation of the example.
+func parseExample(line string) (*model.Example, error) {
+	e := &model.Example{}
+	var (
+		word    strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+		end     = len(line)
+	)
+	for i, r := range line {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inWord = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t':
+			if inWord {
+				e.Words = append(e.Words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case r == '#' && !inWord:
+			end = i
+			e.Description = strings.TrimSpace(line[i+1:])
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+		if end != len(line) {
+			break
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, errors.New("unterminated quote or escape")
+	}
+	if inWord {
+		e.Words = append(e.Words, word.String())
+	}
+	if len(e.Words) == 0 {
+		return nil, errors.New("no command line")
+	}
+	e.Command = strings.TrimSpace(line[:end])
+	return e, nil
+}
@@ -0,0 +1,50 @@
+package commentv1
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"github.com/arran4/go-subcommand/model"
+)
+
+// hook describes the function a Before or After directive names. It must be a function
+// of the same package that returns nothing or an error. Each of its parameters is passed
+// the field of the same name of the command or one of its ancestors, except for a
+// context.Context, which receives the command's context.
+func (cst *CommandsTree) hook(importPath, packageName, name string) (*model.Hook, error) {
+	fn := cst.Funcs[importPath+"."+name]
+	if fn == nil {
+		return nil, fmt.Errorf("%s is not declared in package %s", name, packageName)
+	}
+	if fn.TypeParams != nil {
+		return nil, fmt.Errorf("%s must not be generic", name)
+	}
+	h := &model.Hook{Func: name, ImportPath: importPath}
+	if packageName != "main" {
+		h.Package = packageName
+	}
+	if fn.Params != nil {
+		contextName := cst.FuncContexts[importPath+"."+name]
+		for _, field := range fn.Params.List {
+			isContext := contextName != "" && types.ExprString(field.Type) == contextName
+			if len(field.Names) == 0 && !isContext {
+				return nil, fmt.Errorf("the parameters of %s must be named after the flags they receive", name)
+			}
+			for _, n := range field.Names {
+				h.Params = append(h.Params, model.HookParam{Name: n.Name, IsContext: isContext})
+			}
+			if len(field.Names) == 0 {
+				h.Params = append(h.Params, model.HookParam{IsContext: true})
+			}
+		}
+	}
+	if fn.Results != nil && fn.Results.NumFields() > 0 {
+		id, ok := fn.Results.List[0].Type.(*ast.Ident)
+		if fn.Results.NumFields() > 1 || !ok || id.Name != "error" {
+			return nil, fmt.Errorf("%s must return nothing or an error", name)
+		}
+		h.ReturnsError = true
+	}
+	return h, nil
+}
@@ -5,6 +5,8 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/arran4/go-subcommand/model"
 )
 
 func TestParseGoFile(t *testing.T) {
@@ -70,15 +72,16 @@ func NotACmd() {}
 			wantMissing: true,
 		},
 		{
-			name: "Subcommand with receiver (ignored)",
+			name: "Subcommand with receiver",
 			src: `package main
 
 type T struct{}
 
-// Method is a subcommand that should be ignored
+// Method is a subcommand that runs on a T
 func (t *T) Method() {}
 `,
-			wantMissing: true,
+			wantCmdName:     "method",
+			wantDescription: "runs on a T",
 		},
 	}
 
@@ -166,6 +169,458 @@ func ParseLocal(value string) (string, error) { return value, nil }
 	}
 }
 
+func TestParseGoFile_ContextParameter(t *testing.T) {
+	const source = `package app
+
+import c "context"
+
+// Run is a subcommand ` + "`app run`" + ` -- Run it
+//
+// Flags:
+//
+//	dir: --dir Directory to run in
+func Run(ctx c.Context, d string) error { return nil }
+`
+
+	commands := &CommandsTree{Commands: make(map[string]*CommandTree)}
+	if err := ParseGoFile(token.NewFileSet(), "run.go", "example.com/app", strings.NewReader(source), commands); err != nil {
+		t.Fatalf("ParseGoFile() error = %v", err)
+	}
+
+	params := commands.Commands["app"].SubCommands["run"].SubCommand.Parameters
+	if len(params) != 2 {
+		t.Fatalf("got %d parameters, want 2", len(params))
+	}
+	if !params[0].IsContext() || !params[0].HasGenerator() || params[0].Type != "context.Context" {
+		t.Errorf("ctx parameter = %#v, want a context parameter", params[0])
+	}
+	if got, want := params[1].FlagAliases, []string{"dir"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("d aliases = %v, want %v; the context must not claim the Flags entry", got, want)
+	}
+}
+
+func TestParseGoFile_StreamParameters(t *testing.T) {
+	const source = `package app
+
+import "io"
+
+// Copy is a subcommand ` + "`app copy`" + ` -- Copy input to output
+//
+// Flags:
+//
+//	in: (stdin) Input
+//	out: --out (stdout) Output
+//	name: --name Name of the copy, or - to write to (stdout) instead
+func Copy(in io.Reader, out io.Writer, name string) error { return nil }
+`
+
+	commands := &CommandsTree{Commands: make(map[string]*CommandTree)}
+	if err := ParseGoFile(token.NewFileSet(), "copy.go", "example.com/app", strings.NewReader(source), commands); err != nil {
+		t.Fatalf("ParseGoFile() error = %v", err)
+	}
+
+	params := commands.Commands["app"].SubCommands["copy"].SubCommand.Parameters
+	if len(params) != 3 {
+		t.Fatalf("got %d parameters, want 3", len(params))
+	}
+	for i, want := range []string{"Stdin", "Stdout", ""} {
+		if got := params[i].StreamMethod(); got != want {
+			t.Errorf("%s StreamMethod() = %q, want %q", params[i].Name, got, want)
+		}
+	}
+	if !params[1].HasGenerator() || params[2].HasGenerator() {
+		t.Error("only stream parameters should be filled in by the generated code")
+	}
+	if params[2].Type != "string" || !strings.Contains(params[2].Description, "(stdout)") {
+		t.Errorf("name = %s %q, want a string flag whose description mentions (stdout)", params[2].Type, params[2].Description)
+	}
+}
+
+func TestParseGoFile_StructParameter(t *testing.T) {
+	const source = `package app
+
+// Deploy is a subcommand ` + "`app deploy`" + ` -- Deploy a service
+func Deploy(opts Options) error { return nil }
+
+// Options configures a deployment.
+type Options struct {
+	// -r --region Region to deploy to
+	Region string
+	Net    Net    // --network
+	Target string // @1 Service to deploy
+	Shared
+
+	hidden string
+}
+
+type Net struct {
+	Port int // (default: 8080) Port to expose
+}
+
+type Shared struct {
+	DryRun bool
+}
+`
+
+	commands := &CommandsTree{Commands: make(map[string]*CommandTree)}
+	if err := ParseGoFile(token.NewFileSet(), "deploy.go", "example.com/app", strings.NewReader(source), commands); err != nil {
+		t.Fatalf("ParseGoFile() error = %v", err)
+	}
+
+	sc := commands.Commands["app"].SubCommands["deploy"].SubCommand
+	if len(sc.Signature) != 1 || sc.Signature[0].Name != "opts" || sc.Signature[0].Type != "app.Options" {
+		t.Fatalf("Signature = %#v, want the single opts app.Options parameter", sc.Signature)
+	}
+	want := []struct {
+		name    string
+		aliases []string
+	}{
+		{"opts.Region", []string{"region", "r"}},
+		{"opts.Net.Port", []string{"network-port"}},
+		{"opts.Target", []string{"target"}},
+		{"opts.Shared.DryRun", []string{"dry-run"}},
+	}
+	if len(sc.Parameters) != len(want) {
+		t.Fatalf("got %d parameters, want %d", len(sc.Parameters), len(want))
+	}
+	for i, w := range want {
+		p := sc.Parameters[i]
+		if p.Name != w.name || !reflect.DeepEqual(p.FlagAliases, w.aliases) {
+			t.Errorf("parameter %d = %s %v, want %s %v", i, p.Name, p.FlagAliases, w.name, w.aliases)
+		}
+	}
+	if !sc.Parameters[2].IsPositional || sc.Parameters[2].ArgName() != "target" {
+		t.Errorf("Target should be the positional argument <target>, got %#v", sc.Parameters[2])
+	}
+	if sc.Parameters[1].Default != "8080" {
+		t.Errorf("nested default = %q, want 8080", sc.Parameters[1].Default)
+	}
+}
+
+func TestParseGoFile_MethodReceiver(t *testing.T) {
+	const source = `package app
+
+type Server struct{}
+
+func NewServer() (*Server, error) { return &Server{}, nil }
+
+// (s *Server) Start is a subcommand ` + "`app start`" + ` -- Start it
+//
+// Receiver: NewServer
+func (s *Server) Start() error { return nil }
+
+// (Server) Stop is a subcommand ` + "`app stop`" + ` -- Stop it
+func (Server) Stop() {}
+`
+
+	commands := &CommandsTree{Commands: make(map[string]*CommandTree)}
+	if err := ParseGoFile(token.NewFileSet(), "server.go", "example.com/app", strings.NewReader(source), commands); err != nil {
+		t.Fatalf("ParseGoFile() error = %v", err)
+	}
+
+	start := commands.Commands["app"].SubCommands["start"].SubCommand
+	want := &model.Receiver{Name: "s", Type: "Server", Package: "app", Pointer: true, Constructor: "NewServer", ConstructorReturnsError: true}
+	if !reflect.DeepEqual(start.Receiver, want) {
+		t.Errorf("start Receiver = %#v, want %#v", start.Receiver, want)
+	}
+	if got := start.Callee(); got != "recv.Start" {
+		t.Errorf("Callee() = %q, want recv.Start", got)
+	}
+	stop := commands.Commands["app"].SubCommands["stop"].SubCommand
+	if stop.Receiver == nil || stop.Receiver.Constructor != "" || stop.Receiver.Pointer {
+		t.Errorf("stop Receiver = %#v, want the zero value Server", stop.Receiver)
+	}
+}
+
+func TestParseGoFile_MethodReceiverErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		decls   string
+		wantErr string
+	}{
+		{"missing constructor", "", "NewServer is not declared"},
+		{"constructor with arguments", "func NewServer(name string) *Server { return nil }", "must not take arguments"},
+		{"wrong type", "type Other struct{}\nfunc NewServer() *Other { return nil }", "returns *Other, not Server"},
+		{"second result not error", "func NewServer() (*Server, bool) { return nil, false }", "must return Server or (Server, error)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := `package app
+
+type Server struct{}
+
+` + tt.decls + `
+
+// (s *Server) Start is a subcommand ` + "`app start`" + ` -- Start it
+//
+// Receiver: NewServer
+func (s *Server) Start() {}
+`
+			commands := &CommandsTree{Commands: make(map[string]*CommandTree)}
+			err := ParseGoFile(token.NewFileSet(), "server.go", "example.com/app", strings.NewReader(source), commands)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ParseGoFile() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+			if !strings.HasPrefix(err.Error(), "server.go:") {
+				t.Errorf("error %q should start with the method's position", err)
+			}
+		})
+	}
+}
+
+func TestParseGoFile_ReturnValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		results string
+		want    bool
+		wantErr string
+	}{
+		{"error", "error", false, ""},
+		{"value and error", "([]string, error)", true, ""},
+		{"named value and error", "(names []string, err error)", true, ""},
+		{"two values", "(string, int)", false, "only (T, error) is supported"},
+		{"error first", "(error, string)", false, "only (T, error) is supported"},
+		{"three values", "(string, int, error)", false, "only (T, error) is supported"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := `package app
+
+// List is a subcommand ` + "`app list`" + ` -- List things
+func List() ` + tt.results + ` { panic("") }
+`
+			commands := &CommandsTree{Commands: make(map[string]*CommandTree)}
+			err := ParseGoFile(token.NewFileSet(), "list.go", "example.com/app", strings.NewReader(source), commands)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseGoFile() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseGoFile() error = %v", err)
+			}
+			if got := commands.Commands["app"].SubCommands["list"].SubCommand.ReturnsValue(); got != tt.want {
+				t.Errorf("ReturnsValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGoFile_Hooks(t *testing.T) {
+	tests := []struct {
+		name    string
+		hook    string
+		want    []model.HookParam
+		wantErr string
+		// context is the name the context package is imported as, if not context.
+		context string
+	}{
+		{"no parameters", "func Setup() {}", nil, "", ""},
+		{"context and flag", "func Setup(ctx context.Context, dsn string) error { return nil }", []model.HookParam{{Name: "ctx", IsContext: true}, {Name: "dsn"}}, "", ""},
+		{"unnamed context", "func Setup(context.Context) {}", []model.HookParam{{IsContext: true}}, "", ""},
+		{"aliased context", "func Setup(ctx stdctx.Context, dsn string) {}", []model.HookParam{{Name: "ctx", IsContext: true}, {Name: "dsn"}}, "", "stdctx"},
+		{"unnamed flag", "func Setup(string) {}", nil, "must be named", ""},
+		{"returns a value", "func Setup() int { return 0 }", nil, "must return nothing or an error", ""},
+		{"generic", "func Setup[T any]() {}", nil, "must not be generic", ""},
+		{"undeclared", "", nil, "Setup is not declared in package app", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			context := "context"
+			if tt.context != "" {
+				context = tt.context
+			}
+			source := `package app
+
+import ` + context + ` "context"
+
+var _ ` + context + `.Context
+
+// List is a subcommand ` + "`app list`" + ` -- List things
+//
+// Before: Setup
+func List() {}
+
+` + tt.hook + `
+`
+			commands := &CommandsTree{Commands: make(map[string]*CommandTree)}
+			err := ParseGoFile(token.NewFileSet(), "list.go", "example.com/app", strings.NewReader(source), commands)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseGoFile() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseGoFile() error = %v", err)
+			}
+			hook := commands.Commands["app"].SubCommands["list"].SubCommand.Before
+			if hook == nil || hook.Func != "Setup" || !reflect.DeepEqual(hook.Params, tt.want) {
+				t.Errorf("Before = %+v, want Setup with parameters %+v", hook, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGoFile_ExitCodes(t *testing.T) {
+	tests := []struct {
+		name    string
+		entry   string
+		want    bool
+		wantErr string
+	}{
+		{"variable", "ErrNotFound=3", false, ""},
+		{"struct type", "*ConflictError=4", true, ""},
+		{"string type", "QuotaError=5", true, ""},
+		{"undeclared", "ErrGone=6", false, "ErrGone is not an error variable or non-generic type of package example.com/app"},
+		{"pointer to a variable", "*ErrNotFound=7", false, "*ErrNotFound is not an error variable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := `package app
+
+import "errors"
+
+var ErrNotFound = errors.New("not found")
+
+type ConflictError struct{ Name string }
+
+func (e *ConflictError) Error() string { return e.Name }
+
+type QuotaError string
+
+func (e QuotaError) Error() string { return string(e) }
+
+// List is a subcommand ` + "`app list`" + ` -- List things
+//
+// ExitCodes: ` + tt.entry + `
+func List() error { return nil }
+`
+			commands := &CommandsTree{Commands: make(map[string]*CommandTree)}
+			err := ParseGoFile(token.NewFileSet(), "list.go", "example.com/app", strings.NewReader(source), commands)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseGoFile() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseGoFile() error = %v", err)
+			}
+			codes := commands.Commands["app"].ExitCodes
+			if len(codes) != 1 || codes[0].IsType != tt.want {
+				t.Errorf("ExitCodes = %+v, want one with IsType %v", codes, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGoFile_ValueCheckErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   string
+		wantErr string
+	}{
+		{"max not an int", "--port (max: 6x) Port", `serve.go:9:2: function Serve: parameter port: max "6x" is not a valid int`},
+		{"min not an int", "--port (min: soon) Port", `parameter port: min "soon" is not a valid int`},
+		{"minlen not an integer", "--port (minlen: two) Port", `parameter port: minlen "two" is not a positive integer`},
+		{"maxlen not positive", "--port (maxlen: 0) Port", `parameter port: maxlen "0" is not a positive integer`},
+		{"valid", "--port (min: 1; max: 65535) Port", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := `package app
+
+// Serve is a subcommand ` + "`app serve`" + ` -- Serve requests
+//
+// Flags:
+//
+//	port: ` + tt.flags + `
+func Serve(
+	port int,
+) {}
+`
+			commands := &CommandsTree{Commands: make(map[string]*CommandTree)}
+			err := ParseGoFile(token.NewFileSet(), "serve.go", "example.com/app", strings.NewReader(source), commands)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("ParseGoFile() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ParseGoFile() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	const structSource = `package app
+
+type Options struct {
+	Limit int // --limit (max: lots) Most items
+}
+
+// List is a subcommand ` + "`app list`" + ` -- List items
+func List(opts Options) {}
+`
+	commands := &CommandsTree{Commands: make(map[string]*CommandTree)}
+	err := ParseGoFile(token.NewFileSet(), "list.go", "example.com/app", strings.NewReader(structSource), commands)
+	if want := `list.go:4:2: field opts.Limit: parameter opts.Limit: max "lots" is not a valid int`; err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("ParseGoFile() error = %v, want it to contain %q", err, want)
+	}
+}
+
+func TestParseGoFile_HelpTopics(t *testing.T) {
+	tests := []struct {
+		name    string
+		decl    string
+		want    *model.HelpTopic
+		wantErr string
+	}{
+		{
+			name: "const with description",
+			decl: "// EnvHelp is a help topic 'app Environment' -- Environment variables\nconst EnvHelp = `\nAPP_HOME is the home.\n`",
+			want: &model.HelpTopic{Name: "environment", Description: "Environment variables", Text: "APP_HOME is the home."},
+		},
+		{
+			name: "grouped var without description",
+			decl: "var (\n\tother = 1\n\t// ConfigHelp is a help topic `app config`\n\tConfigHelp = \"First line.\\n\" + \"Second line.\"\n)",
+			want: &model.HelpTopic{Name: "config", Description: "First line.", Text: "First line.\nSecond line."},
+		},
+		{
+			name:    "not a literal",
+			decl:    "// EnvHelp is a help topic 'app environment'\nvar EnvHelp = strings.TrimSpace(\"x\")",
+			wantErr: "must be a string constant or variable initialised with a literal",
+		},
+		{
+			name:    "no topic",
+			decl:    "// EnvHelp is a help topic 'app'\nconst EnvHelp = \"x\"",
+			wantErr: "must name a command and a topic",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := "package app\n\n" + tt.decl + "\n"
+			commands := &CommandsTree{Commands: make(map[string]*CommandTree)}
+			err := ParseGoFile(token.NewFileSet(), "topics.go", "example.com/app", strings.NewReader(source), commands)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseGoFile() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseGoFile() error = %v", err)
+			}
+			topics := commands.Commands["app"].HelpTopics
+			if len(topics) != 1 || !reflect.DeepEqual(topics[0], tt.want) {
+				t.Errorf("HelpTopics = %+v, want %+v", topics, tt.want)
+			}
+		})
+	}
+}
+
 func getKeys(m map[string]*CommandTree) []string {
 	keys := make([]string, 0, len(m))
 	for k := range m {
@@ -353,3 +808,128 @@ Flags:
 		})
 	}
 }
+
+func TestParseSubCommandDoc_Directives(t *testing.T) {
+	tests := []struct {
+		name              string
+		text              string
+		wantEnvPrefix     string
+		wantConfigFormats []string
+		wantFlagGroups    []*model.FlagGroup
+		wantHidden        bool
+		wantDeprecation   string
+		wantFlagParsing   string
+		wantExitCodes     []*model.ExitCode
+		wantExamples      []*model.Example
+		wantReceiver      string
+		wantHelp          string
+	}{
+		{
+			name:          "EnvPrefix",
+			text:          "Root is a subcommand `app`\nEnvPrefix: APP",
+			wantEnvPrefix: "APP",
+		},
+		{
+			name:              "ConfigFile Default Format",
+			text:              "Root is a subcommand `app`\nConfigFile:",
+			wantConfigFormats: []string{"json"},
+		},
+		{
+			name:              "ConfigFile Formats",
+			text:              "Root is a subcommand `app`\nconfigfile: JSON, toml, ini",
+			wantConfigFormats: []string{"json", "ini"},
+		},
+		{
+			name:              "ConfigFile Unknown Format",
+			text:              "Root is a subcommand `app`\nConfigFile: yaml",
+			wantConfigFormats: []string{"json"},
+		},
+		{
+			name: "Flag Groups",
+			text: "Root is a subcommand `app`\nOneOf: json, --yaml\noneof: a, b\nAtLeastOne: name, all",
+			wantFlagGroups: []*model.FlagGroup{
+				{Kind: model.FlagGroupOneOf, Names: []string{"json", "yaml"}},
+				{Kind: model.FlagGroupOneOf, Names: []string{"a", "b"}},
+				{Kind: model.FlagGroupAtLeastOne, Names: []string{"name", "all"}},
+			},
+		},
+		{
+			name:            "FlagParsing",
+			text:            "Root is a subcommand `app`\nFlagParsing: Interspersed",
+			wantFlagParsing: model.FlagParsingInterspersed,
+		},
+		{
+			name: "FlagParsing Unknown Mode",
+			text: "Root is a subcommand `app`\nFlagParsing: gnu",
+		},
+		{
+			name: "ExitCodes",
+			text: "Root is a subcommand `app`\nExitCodes: ErrNotFound=3, *ConflictError = 4\nexitcodes: ErrGone=5, bad, ErrX=x",
+			wantExitCodes: []*model.ExitCode{
+				{Error: "ErrNotFound", Code: 3},
+				{Error: "*ConflictError", IsType: true, Code: 4},
+				{Error: "ErrGone", Code: 5},
+			},
+		},
+		{
+			name: "Examples",
+			text: "Greet is a subcommand `app greet`\nExamples:\n\n\tapp greet --name Bob  # Greet Bob\n\tapp greet -n \"Mary Ann\" 'a # b' \\#x\n\tapp greet \"unterminated\nHidden",
+			wantExamples: []*model.Example{
+				{Command: "app greet --name Bob", Words: []string{"app", "greet", "--name", "Bob"}, Description: "Greet Bob"},
+				{Command: `app greet -n "Mary Ann" 'a # b' \#x`, Words: []string{"app", "greet", "-n", "Mary Ann", "a # b", "#x"}},
+			},
+			wantHidden: true,
+		},
+		{
+			name:            "Hidden and Deprecated",
+			text:            "Old is a subcommand `app old`\nHidden\nDeprecated: use \"app new\" instead",
+			wantHidden:      true,
+			wantDeprecation: `use "app new" instead`,
+		},
+		{
+			name:         "Receiver",
+			text:         "Start is a subcommand `app start`\nReceiver: NewServer",
+			wantReceiver: "NewServer",
+		},
+		{
+			name:     "Prose Starting Like Directives",
+			text:     "Old is a subcommand `app old`\nDeprecated: use X instead of the old\ncommand.\n\nReceiver: the server answering requests.\nOneOf: the modes must be chosen.\nExitCodes: 3 when nothing matched.\nFlagParsing: strict by default.\nConfigFile: read from the home directory.",
+			wantHelp: "Deprecated: use X instead of the old\ncommand.\n\nReceiver: the server answering requests.\nOneOf: the modes must be chosen.\nExitCodes: 3 when nothing matched.\nFlagParsing: strict by default.\nConfigFile: read from the home directory.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, ok := ParseSubCommandDoc(tt.text)
+			if !ok {
+				t.Fatal("expected a subcommand")
+			}
+			if doc.EnvPrefix != tt.wantEnvPrefix {
+				t.Errorf("EnvPrefix = %q, want %q", doc.EnvPrefix, tt.wantEnvPrefix)
+			}
+			if !reflect.DeepEqual(doc.ConfigFormats, tt.wantConfigFormats) {
+				t.Errorf("ConfigFormats = %v, want %v", doc.ConfigFormats, tt.wantConfigFormats)
+			}
+			if !reflect.DeepEqual(doc.FlagGroups, tt.wantFlagGroups) {
+				t.Errorf("FlagGroups = %v, want %v", doc.FlagGroups, tt.wantFlagGroups)
+			}
+			if doc.FlagParsing != tt.wantFlagParsing {
+				t.Errorf("FlagParsing = %q, want %q", doc.FlagParsing, tt.wantFlagParsing)
+			}
+			if !reflect.DeepEqual(doc.ExitCodes, tt.wantExitCodes) {
+				t.Errorf("ExitCodes = %+v, want %+v", doc.ExitCodes, tt.wantExitCodes)
+			}
+			if !reflect.DeepEqual(doc.Examples, tt.wantExamples) {
+				t.Errorf("Examples = %+v, want %+v", doc.Examples, tt.wantExamples)
+			}
+			if doc.Hidden != tt.wantHidden || doc.Deprecated != (tt.wantDeprecation != "") || doc.DeprecationNote != tt.wantDeprecation {
+				t.Errorf("Hidden/Deprecated = %v/%v %q, want %v %q", doc.Hidden, doc.Deprecated, doc.DeprecationNote, tt.wantHidden, tt.wantDeprecation)
+			}
+			if doc.Receiver != tt.wantReceiver {
+				t.Errorf("Receiver = %q, want %q", doc.Receiver, tt.wantReceiver)
+			}
+			if doc.ExtendedHelp != tt.wantHelp {
+				t.Errorf("ExtendedHelp = %q, want %q", doc.ExtendedHelp, tt.wantHelp)
+			}
+		})
+	}
+}
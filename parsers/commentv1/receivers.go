@@ -0,0 +1,75 @@
+package commentv1
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"github.com/arran4/go-subcommand/model"
+)
+
+// methodReceiver describes the receiver of a method command. constructor is the value of
+// the method's Receiver directive; when set it must name a function of the same package
+// that takes no arguments and returns the receiver type, or a pointer to it, optionally
+// followed by an error.
+func (cst *CommandsTree) methodReceiver(importPath, packageName string, recv *ast.FieldList, constructor string) (*model.Receiver, error) {
+	if recv == nil || len(recv.List) != 1 {
+		return nil, fmt.Errorf("expected a single receiver")
+	}
+	field := recv.List[0]
+	r := &model.Receiver{Constructor: constructor}
+	if packageName != "main" {
+		r.Package = packageName
+	}
+	if len(field.Names) > 0 && field.Names[0].Name != "_" {
+		r.Name = field.Names[0].Name
+	}
+	expr := field.Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		r.Pointer = true
+		expr = star.X
+	}
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return nil, fmt.Errorf("generic receiver types are not supported")
+	}
+	r.Type = ident.Name
+	if constructor == "" {
+		return r, nil
+	}
+
+	fn := cst.Funcs[importPath+"."+constructor]
+	if fn == nil {
+		return nil, fmt.Errorf("receiver constructor %s is not declared in package %s", constructor, packageName)
+	}
+	if fn.TypeParams != nil || fn.Params.NumFields() != 0 {
+		return nil, fmt.Errorf("receiver constructor %s must not take arguments", constructor)
+	}
+	var results []ast.Expr
+	if fn.Results != nil {
+		for _, res := range fn.Results.List {
+			n := max(len(res.Names), 1)
+			for range n {
+				results = append(results, res.Type)
+			}
+		}
+	}
+	switch len(results) {
+	case 1:
+	case 2:
+		if id, ok := results[1].(*ast.Ident); !ok || id.Name != "error" {
+			return nil, fmt.Errorf("receiver constructor %s must return %s or (%s, error)", constructor, r.Type, r.Type)
+		}
+		r.ConstructorReturnsError = true
+	default:
+		return nil, fmt.Errorf("receiver constructor %s must return %s or (%s, error)", constructor, r.Type, r.Type)
+	}
+	got := results[0]
+	if star, ok := got.(*ast.StarExpr); ok {
+		got = star.X
+	}
+	if id, ok := got.(*ast.Ident); !ok || id.Name != r.Type {
+		return nil, fmt.Errorf("receiver constructor %s returns %s, not %s", constructor, types.ExprString(results[0]), r.Type)
+	}
+	return r, nil
+}
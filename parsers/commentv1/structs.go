@@ -0,0 +1,182 @@
+package commentv1
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"github.com/arran4/go-subcommand/model"
+	"github.com/arran4/go-subcommand/parsers"
+)
+
+// addDeclarations records the types, variables and functions declared in f, so that
+// struct parameters can be expanded, receiver constructors looked up and exit code errors
+// told apart wherever in the package they are declared.
+func (cst *CommandsTree) addDeclarations(importPath string, f *ast.File) {
+	contextName := contextTypeName(f)
+	for _, decl := range f.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok {
+			if fn.Recv == nil {
+				if cst.Funcs == nil {
+					cst.Funcs = make(map[string]*ast.FuncType)
+					cst.FuncContexts = make(map[string]string)
+				}
+				cst.Funcs[importPath+"."+fn.Name.Name] = fn.Type
+				cst.FuncContexts[importPath+"."+fn.Name.Name] = contextName
+			}
+			continue
+		}
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			if vs, ok := spec.(*ast.ValueSpec); ok && gen.Tok == token.VAR {
+				for _, name := range vs.Names {
+					cst.addName(importPath, name.Name, token.VAR)
+				}
+				continue
+			}
+			ts, ok := spec.(*ast.TypeSpec)
+			if !ok || ts.TypeParams != nil {
+				continue
+			}
+			cst.addName(importPath, ts.Name.Name, token.TYPE)
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			if cst.StructTypes == nil {
+				cst.StructTypes = make(map[string]*ast.StructType)
+			}
+			cst.StructTypes[importPath+"."+ts.Name.Name] = st
+		}
+	}
+}
+
+func (cst *CommandsTree) addName(importPath, name string, tok token.Token) {
+	if cst.Names == nil {
+		cst.Names = make(map[string]token.Token)
+	}
+	cst.Names[importPath+"."+name] = tok
+}
+
+// structType returns the struct declared in the package at importPath that expr names,
+// or nil when expr is not a struct type of that package.
+func (cst *CommandsTree) structType(importPath string, expr ast.Expr) (string, *ast.StructType) {
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return "", nil
+	}
+	st := cst.StructTypes[importPath+"."+ident.Name]
+	if st == nil {
+		return "", nil
+	}
+	return ident.Name, st
+}
+
+// structExpander turns the exported fields of a struct parameter into flag parameters.
+// Field comments use the same syntax as a Flags: block entry; the inline comment wins
+// over the doc comment. Nested structs contribute their fields with the nested field's
+// flag name, or its kebab-cased name, as a prefix.
+type structExpander struct {
+	cmdTree    *CommandsTree
+	fset       *token.FileSet
+	importPath string
+	cmdName    string
+	// seen holds the struct types being expanded, to stop on recursive definitions.
+	seen map[string]bool
+}
+
+func (e *structExpander) expand(typeName string, st *ast.StructType, path, flagPrefix string) ([]*model.FunctionParameter, error) {
+	if e.seen[typeName] {
+		return nil, fmt.Errorf("struct %s contains itself", typeName)
+	}
+	e.seen[typeName] = true
+	defer delete(e.seen, typeName)
+
+	var params []*model.FunctionParameter
+	for _, field := range st.Fields.List {
+		comments := fieldComments(field)
+		if len(field.Names) == 0 {
+			// An embedded struct adds its fields without a prefix.
+			nestedName, nested := e.cmdTree.structType(e.importPath, field.Type)
+			if nested == nil {
+				continue
+			}
+			fields, err := e.expand(nestedName, nested, path+"."+nestedName, flagPrefix)
+			if err != nil {
+				return nil, err
+			}
+			params = append(params, fields...)
+			continue
+		}
+		for _, name := range field.Names {
+			if !name.IsExported() {
+				continue
+			}
+			fieldPath := path + "." + name.Name
+			if nestedName, nested := e.cmdTree.structType(e.importPath, field.Type); nested != nil {
+				prefix := parsers.ToKebabCase(name.Name)
+				var nestedParam model.FunctionParameter
+				for _, c := range comments {
+					c.mergeInto(&nestedParam)
+				}
+				for _, f := range nestedParam.FlagAliases {
+					if len(f) > 1 {
+						prefix = f
+						break
+					}
+				}
+				fields, err := e.expand(nestedName, nested, fieldPath, flagPrefix+prefix+"-")
+				if err != nil {
+					return nil, err
+				}
+				params = append(params, fields...)
+				continue
+			}
+			typeName, err := formatType(field.Type)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", fieldPath, err)
+			}
+			fp := &model.FunctionParameter{
+				Name:       fieldPath,
+				Type:       typeName,
+				DeclaredIn: e.cmdName,
+			}
+			for _, c := range comments {
+				c.mergeInto(fp)
+			}
+			if err := checkParsedParams(fp, comments); err != nil {
+				return nil, fmt.Errorf("%s: field %s: %w", e.fset.Position(name.Pos()), fieldPath, err)
+			}
+			if len(fp.FlagAliases) == 0 {
+				fp.FlagAliases = []string{parsers.ToKebabCase(name.Name)}
+			}
+			for i, f := range fp.FlagAliases {
+				if len(f) > 1 {
+					fp.FlagAliases[i] = flagPrefix + f
+				}
+			}
+			if fp.IsVarArg {
+				fp.IsPositional = true
+			}
+			params = append(params, fp)
+		}
+	}
+	return params, nil
+}
+
+// fieldComments parses the doc and inline comments of a struct field, lowest priority
+// first, so the inline comment wins when both set the same attribute.
+func fieldComments(field *ast.Field) []ParsedParam {
+	var comments []ParsedParam
+	if field.Doc != nil {
+		comments = append(comments, parseParamDetails(strings.TrimSpace(field.Doc.Text())))
+	}
+	if field.Comment != nil {
+		comments = append(comments, parseParamDetails(strings.TrimSpace(field.Comment.Text())))
+	}
+	return comments
+}
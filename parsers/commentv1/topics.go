@@ -0,0 +1,104 @@
+package commentv1
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/arran4/go-subcommand/model"
+)
+
+// reHelpTopic matches the first line of a help topic declaration, e.g.
+// "EnvHelp is a help topic 'app environment' -- Environment variables".
+var reHelpTopic = regexp.MustCompile("^(\\w+) is a help topic ['`]([^'`]*)['`]\\s*(.*)$")
+
+// addHelpTopics collects the help topics declared on the constants and variables of decl.
+// The text of a topic is the string literal, or concatenation of literals, the
+// documented name is given.
+func (cst *CommandsTree) addHelpTopics(fset *token.FileSet, decl *ast.GenDecl) error {
+	if decl.Tok != token.CONST && decl.Tok != token.VAR {
+		return nil
+	}
+	for _, spec := range decl.Specs {
+		vs, ok := spec.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+		doc := vs.Doc
+		if doc == nil && !decl.Lparen.IsValid() {
+			doc = decl.Doc
+		}
+		if doc == nil {
+			continue
+		}
+		for _, line := range strings.Split(doc.Text(), "\n") {
+			m := reHelpTopic.FindStringSubmatch(strings.TrimSpace(line))
+			if m == nil {
+				continue
+			}
+			fields := strings.Fields(m[2])
+			if len(fields) != 2 {
+				return fmt.Errorf("%s: help topic %q must name a command and a topic, as in 'app environment'", fset.Position(vs.Pos()), m[2])
+			}
+			text, ok := "", false
+			for i, name := range vs.Names {
+				if name.Name == m[1] && i < len(vs.Values) {
+					text, ok = stringLiteral(vs.Values[i])
+				}
+			}
+			if !ok {
+				return fmt.Errorf("%s: help topic %s must be a string constant or variable initialised with a literal", fset.Position(vs.Pos()), m[1])
+			}
+			text = strings.Trim(text, "\n")
+			description := strings.TrimSpace(m[3])
+			if rest, found := strings.CutPrefix(description, "-- "); found {
+				description = rest
+			} else if rest, found := strings.CutPrefix(description, "that "); found {
+				description = rest
+			}
+			if description == "" {
+				description, _, _ = strings.Cut(text, "\n")
+			}
+
+			cmdName := fields[0]
+			ct, ok := cst.Commands[cmdName]
+			if !ok {
+				ct = &CommandTree{
+					CommandName:    cmdName,
+					SubCommandTree: NewSubCommandTree(nil),
+				}
+				cst.Commands[cmdName] = ct
+			}
+			ct.HelpTopics = append(ct.HelpTopics, &model.HelpTopic{Name: strings.ToLower(fields[1]), Description: description, Text: text})
+		}
+	}
+	return nil
+}
+
+// stringLiteral returns the value of a string literal or of a concatenation of them.
+func stringLiteral(expr ast.Expr) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return "", false
+		}
+		s, err := strconv.Unquote(e.Value)
+		return s, err == nil
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return "", false
+		}
+		x, ok := stringLiteral(e.X)
+		if !ok {
+			return "", false
+		}
+		y, ok := stringLiteral(e.Y)
+		return x + y, ok
+	case *ast.ParenExpr:
+		return stringLiteral(e.X)
+	}
+	return "", false
+}
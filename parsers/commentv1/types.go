@@ -0,0 +1,233 @@
+package commentv1
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"github.com/arran4/go-subcommand/model"
+	"golang.org/x/tools/go/packages"
+)
+
+// conversionTarget is a parameter whose type the comments alone give no way to parse,
+// along with the function that declares it.
+type conversionTarget struct {
+	importPath string
+	function   string
+	// receiver is the receiver type name of a method command.
+	receiver string
+	param    *model.FunctionParameter
+}
+
+// conversionTargets lists the parameters of commands the generated code cannot parse yet.
+func conversionTargets(commands []*model.Command) []conversionTarget {
+	var targets []conversionTarget
+	add := func(importPath, function string, receiver *model.Receiver, params []*model.FunctionParameter) {
+		if function == "" {
+			return
+		}
+		for _, p := range params {
+			if !p.NeedsConversion() {
+				continue
+			}
+			t := conversionTarget{importPath: importPath, function: function, param: p}
+			if receiver != nil {
+				t.receiver = receiver.Type
+			}
+			targets = append(targets, t)
+		}
+	}
+	var walk func([]*model.SubCommand)
+	walk = func(scs []*model.SubCommand) {
+		for _, sc := range scs {
+			add(sc.ImportPath, sc.SubCommandFunctionName, sc.Receiver, sc.Parameters)
+			walk(sc.SubCommands)
+		}
+	}
+	for _, cmd := range commands {
+		add(cmd.ImportPath, cmd.FunctionName, cmd.Receiver, cmd.Parameters)
+		walk(cmd.SubCommands)
+	}
+	return targets
+}
+
+// resolveParameterTypes loads the packages declaring the targets from dir with their
+// type information and, for every target, looks for an UnmarshalText or Set method on
+// its type or a pointer to it. The type is then written as the generated code refers to
+// it, qualified by the name the declaring file imports its package under. A parameter
+// without such a method is an error reported at its declaration.
+func resolveParameterTypes(dir string, targets []conversionTarget) error {
+	if len(targets) == 0 {
+		return nil
+	}
+
+	patterns := []string{"encoding", "flag"}
+	seen := map[string]bool{}
+	for _, t := range targets {
+		if !seen[t.importPath] {
+			seen[t.importPath] = true
+			patterns = append(patterns, t.importPath)
+		}
+	}
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo,
+		Dir:  dir,
+		Fset: token.NewFileSet(),
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return fmt.Errorf("loading packages for type information: %w", err)
+	}
+	byPath := map[string]*packages.Package{}
+	for _, pkg := range pkgs {
+		byPath[pkg.PkgPath] = pkg
+	}
+	conversions := []struct {
+		method string
+		iface  *types.Interface
+	}{
+		{model.ConversionUnmarshalText, lookupInterface(byPath["encoding"], "TextUnmarshaler")},
+		{model.ConversionSet, lookupInterface(byPath["flag"], "Value")},
+	}
+
+	for _, t := range targets {
+		pkg := byPath[t.importPath]
+		if pkg == nil || pkg.Types == nil {
+			return fmt.Errorf("loading package %s for type information failed", t.importPath)
+		}
+		if len(pkg.Errors) > 0 {
+			return fmt.Errorf("loading package %s for type information: %v", t.importPath, pkg.Errors[0])
+		}
+		v := parameterVar(pkg.Types, t)
+		if v == nil {
+			// Not declared by this function, e.g. a flag it inherits; the declaring
+			// command resolves it.
+			continue
+		}
+		elem := v.Type()
+		if s, ok := elem.(*types.Slice); ok && (t.param.IsVarArg || strings.HasPrefix(t.param.Type, "[]")) {
+			elem = s.Elem()
+		}
+		for _, c := range conversions {
+			if c.iface == nil {
+				continue
+			}
+			_, isPointer := elem.(*types.Pointer)
+			if types.Implements(elem, c.iface) || (!isPointer && types.Implements(types.NewPointer(elem), c.iface)) {
+				t.param.Conversion = c.method
+				break
+			}
+		}
+		if t.param.Conversion == "" {
+			return fmt.Errorf("%s: parameter %s of %s: no known conversion from a command line value to %s; implement encoding.TextUnmarshaler or flag.Value, or set a parser",
+				cfg.Fset.Position(v.Pos()), t.param.Name, t.function, types.TypeString(elem, types.RelativeTo(pkg.Types)))
+		}
+		names := importNames(pkg, v.Pos())
+		qualifier := func(p *types.Package) string {
+			if p.Name() == "main" {
+				return ""
+			}
+			if name, ok := names[p.Path()]; ok {
+				return name
+			}
+			return p.Name()
+		}
+		typeName := types.TypeString(elem, qualifier)
+		if strings.HasPrefix(t.param.Type, "[]") {
+			typeName = "[]" + typeName
+		}
+		t.param.Type = typeName
+		if named, ok := types.Unalias(derefType(elem)).(*types.Named); ok {
+			if p := named.Obj().Pkg(); p != nil && p.Name() != "main" {
+				t.param.TypePackage = &model.FuncRef{ImportPath: p.Path(), PackagePath: p.Path(), CommandPackageName: qualifier(p)}
+			}
+		}
+	}
+	return nil
+}
+
+// parameterVar finds the variable a target parameter is read from: the function's
+// parameter of that name or, for a flag expanded from a struct parameter, the field the
+// rest of its dotted name leads to.
+func parameterVar(pkg *types.Package, t conversionTarget) *types.Var {
+	var fn *types.Func
+	if t.receiver == "" {
+		fn, _ = pkg.Scope().Lookup(t.function).(*types.Func)
+	} else if tn, ok := pkg.Scope().Lookup(t.receiver).(*types.TypeName); ok {
+		if named, ok := tn.Type().(*types.Named); ok {
+			for i := 0; i < named.NumMethods(); i++ {
+				if named.Method(i).Name() == t.function {
+					fn = named.Method(i)
+				}
+			}
+		}
+	}
+	if fn == nil {
+		return nil
+	}
+	parts := strings.Split(t.param.Name, ".")
+	var v *types.Var
+	params := fn.Signature().Params()
+	for i := 0; i < params.Len(); i++ {
+		if params.At(i).Name() == parts[0] {
+			v = params.At(i)
+		}
+	}
+	for _, name := range parts[1:] {
+		if v == nil {
+			return nil
+		}
+		st, ok := derefType(v.Type()).Underlying().(*types.Struct)
+		if !ok {
+			return nil
+		}
+		v = nil
+		for i := 0; i < st.NumFields(); i++ {
+			if st.Field(i).Name() == name {
+				v = st.Field(i)
+			}
+		}
+	}
+	return v
+}
+
+// importNames maps the import paths of the file of pkg containing pos to the names the
+// file imports them under, for the imports it renames.
+func importNames(pkg *packages.Package, pos token.Pos) map[string]string {
+	names := map[string]string{}
+	for _, f := range pkg.Syntax {
+		if pos < f.FileStart || pos > f.FileEnd {
+			continue
+		}
+		for _, imp := range f.Imports {
+			if imp.Name == nil || imp.Name.Name == "_" || imp.Name.Name == "." {
+				continue
+			}
+			if importPath, err := strconv.Unquote(imp.Path.Value); err == nil {
+				names[importPath] = imp.Name.Name
+			}
+		}
+	}
+	return names
+}
+
+func lookupInterface(pkg *packages.Package, name string) *types.Interface {
+	if pkg == nil || pkg.Types == nil {
+		return nil
+	}
+	tn, ok := pkg.Types.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
+		return nil
+	}
+	iface, _ := tn.Type().Underlying().(*types.Interface)
+	return iface
+}
+
+func derefType(t types.Type) types.Type {
+	if p, ok := t.(*types.Pointer); ok {
+		return p.Elem()
+	}
+	return t
+}
@@ -4,29 +4,48 @@ import (
 	"go/token"
 	"strings"
 	"testing"
+	"testing/fstest"
 )
 
 func TestParseGoFile_UnsupportedTypes(t *testing.T) {
-	// Case 1: Map (Should return error, not panic)
-	t.Run("MapType", func(t *testing.T) {
+	// Case 1: Channel (Should return error, not panic)
+	t.Run("ChanType", func(t *testing.T) {
 		src := `package main
 
 // MyCmd is a subcommand ` + "`app cmd`" + `
-func MyCmd(m map[string]int) {}
+func MyCmd(c chan int) {}
 `
 		fset := token.NewFileSet()
 		cmdTree := &CommandsTree{Commands: make(map[string]*CommandTree)}
 
 		err := ParseGoFile(fset, "test.go", "main", strings.NewReader(src), cmdTree)
 		if err == nil {
-			t.Fatal("Expected error for unsupported type (map), got nil")
+			t.Fatal("Expected error for unsupported type (chan), got nil")
 		}
 		if !strings.Contains(err.Error(), "unsupported type") && !strings.Contains(err.Error(), "Unsupported type") {
 			t.Errorf("Expected 'unsupported type' error, got: %v", err)
 		}
 	})
 
-	// Case 2: Pointer (Should be supported)
+	// Case 2: Map (Should be supported)
+	t.Run("MapType", func(t *testing.T) {
+		src := `package main
+
+// MyCmd is a subcommand ` + "`app cmd`" + `
+func MyCmd(m map[string]int) {}
+`
+		fset := token.NewFileSet()
+		cmdTree := &CommandsTree{Commands: make(map[string]*CommandTree)}
+
+		if err := ParseGoFile(fset, "test.go", "main", strings.NewReader(src), cmdTree); err != nil {
+			t.Fatalf("Expected success for map type, got error: %v", err)
+		}
+		if got := cmdTree.Commands["app"].SubCommands["cmd"].SubCommand.Parameters[0].Type; got != "map[string]int" {
+			t.Errorf("Type = %q, want map[string]int", got)
+		}
+	})
+
+	// Case 3: Pointer (Should be supported)
 	t.Run("PointerType", func(t *testing.T) {
 		src := `package main
 
@@ -42,3 +61,25 @@ func MyCmd(p *int) {}
 		}
 	})
 }
+
+func TestParseGoFiles_TypeNeedsDirectory(t *testing.T) {
+	fsys := fstest.MapFS{
+		"go.mod": &fstest.MapFile{Data: []byte("module example.com/test\n\ngo 1.22\n")},
+		"main.go": &fstest.MapFile{Data: []byte(`package app
+
+type Mode int
+
+// App is a subcommand ` + "`app`" + `
+func App(mode Mode) {}
+`)},
+	}
+	_, err := ParseGoFiles(fsys, ".")
+	if err == nil {
+		t.Fatal("Expected an error for a type that needs type information, got nil")
+	}
+	for _, want := range []string{"parameter mode of App", "Mode", "directory"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should contain %q", err, want)
+		}
+	}
+}
@@ -16,6 +16,11 @@ func TestWarnings(t *testing.T) {
 		"main.go": &fstest.MapFile{
 			Data: []byte(`package main
 
+import (
+	"context"
+	"io"
+)
+
 // Root is a subcommand ` + "`root`" + `
 // Flags:
 // 	verbose: -v Verbose output
@@ -24,6 +29,12 @@ func Root(verbose, dryrun bool) {}
 
 // Sub is a subcommand ` + "`root sub`" + `
 func Sub() {}
+
+// Serve is a subcommand ` + "`root serve`" + ` -- Serve requests
+// Flags:
+// 	port: --port Port to listen on
+// 	out: (stdout)
+func Serve(ctx context.Context, port int, out io.Writer) {}
 `),
 		},
 	}
@@ -50,4 +61,7 @@ func Sub() {}
 			t.Errorf("Expected warning not found: %q\nGot output:\n%s", warn, output)
 		}
 	}
+	if strings.Contains(output, "function Serve") {
+		t.Errorf("Parameters that are not flags should not need descriptions\nGot output:\n%s", output)
+	}
 }
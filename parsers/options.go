@@ -3,4 +3,8 @@ package parsers
 type ParseOptions struct {
 	SearchPaths []string
 	Recursive   bool
+	// Dir is the directory on disk the parsed file system is read from. When set,
+	// parameter types the comments alone cannot handle are resolved by loading the
+	// packages with their type information.
+	Dir string
 }
@@ -0,0 +1,36 @@
+package go_subcommand
+
+import (
+	"strings"
+	"testing"
+)
+
+const secretSource = `package main
+
+// Root is a subcommand ` + "`app`" + `
+func Root() {}
+
+// Deploy is a subcommand ` + "`app deploy`" + ` -- Deploy the service
+//
+// Flags:
+//
+//	token: --token (secret) (default: "dev-token") Access token
+func Deploy(token string) {}
+`
+
+func TestSecret_MaskedDefaultAndReader(t *testing.T) {
+	writer := NewCollectingFileWriter()
+	if err := GenerateWithFS(setupProject(t, secretSource), writer, ".", "man", "", "commentv1", nil, false, false, nil, false, false, "", "", ""); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	man := string(mustGeneratedFile(t, writer, "man/app-deploy.1"))
+	assertContains(t, man, "(default: ********)", "man page should mask the secret default")
+	if strings.Contains(man, "dev-token") {
+		t.Errorf("man page shows the secret default:\n%s", man)
+	}
+
+	mustGeneratedFile(t, writer, "cmd/app/gosubc_secret.go")
+	deploy := string(mustGeneratedFile(t, writer, "cmd/app/deploy.go"))
+	assertContains(t, deploy, "readSecret(c.IO.Stdin(), value)", "the flag value should be read through readSecret")
+}
@@ -108,10 +108,21 @@ func TemplateLayout() {
 	fmt.Println("  │   ├── root.go.gotmpl          The root CLI struct and execution loop")
 	fmt.Println("  │   ├── cmd.go.gotmpl           The subcommand structs and execution loops")
 	fmt.Println("  │   ├── main.go.gotmpl          The entry point (main.go) calling the RootCmd")
+	fmt.Println("  │   ├── completion.go.gotmpl    Embeds the shell completion scripts for the completion command")
+	fmt.Println("  │   ├── suggest.go.gotmpl       \"Did you mean\" suggestions for mistyped commands and flags")
+	fmt.Println("  │   ├── output.go.gotmpl        Writes returned values in the --output format")
+	fmt.Println("  │   ├── prompt*.go.gotmpl       Prompts for missing values on a terminal, per platform")
+	fmt.Println("  │   ├── secret.go.gotmpl        Reads secret flag values from @file or stdin")
 	fmt.Println("  │   ├── templates/              Embedded CLI usage templates")
 	fmt.Println("  │   │   ├── usage.txt.gotmpl    The usage description for individual subcommands")
 	fmt.Println("  │   │   ├── templates.go.gotmpl Loader for the generated usage text templates")
-	fmt.Println("  │   │   └── man.gotmpl          Unix manual page template")
+	fmt.Println("  │   │   ├── man.gotmpl          Unix manual page template")
+	fmt.Println("  │   │   └── topic.gotmpl        Unix manual page template for help topics")
+	fmt.Println("  ├── completion/                 Shell completion scripts")
+	fmt.Println("  │   ├── bash.gotmpl             Bash completion")
+	fmt.Println("  │   ├── zsh.gotmpl              Zsh completion")
+	fmt.Println("  │   ├── fish.gotmpl             Fish completion")
+	fmt.Println("  │   └── powershell.gotmpl       PowerShell completion")
 	fmt.Println("  ├── common/                     Common helper templates")
 	fmt.Println("  │   └── common.gotmpl           Shared definitions and imports")
 	fmt.Println("  ├── goreleaser.yml.gotmpl       Configuration template for goreleaser")